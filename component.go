@@ -16,6 +16,23 @@ limitations under the License.
 
 package componego
 
+import (
+	"context"
+	"time"
+)
+
+const (
+	// RestartNever is a policy that does not restart the background service of the component.
+	RestartNever RestartPolicy = 0
+	// RestartOnFailure is a policy that restarts the background service of the component if it returns an error.
+	RestartOnFailure RestartPolicy = 1
+	// RestartAlways is a policy that restarts the background service of the component whenever it stops.
+	RestartAlways RestartPolicy = 2
+)
+
+// RestartPolicy is the type of the policy that describes when the background service of the component is restarted.
+type RestartPolicy int
+
 // Component is an interface that describes the component.
 type Component interface {
 	// ComponentIdentifier returns the component ID.
@@ -26,6 +43,27 @@ type Component interface {
 	ComponentVersion() string
 }
 
+// Metadata is additional information about the component.
+type Metadata struct {
+	// Description is a short description of the component.
+	Description string `json:"description,omitempty"`
+	// Tags are arbitrary labels that can be used to group components.
+	Tags []string `json:"tags,omitempty"`
+	// Owner is the team or the person responsible for the component.
+	Owner string `json:"owner,omitempty"`
+	// DocumentationURL is a link to the component documentation.
+	DocumentationURL string `json:"documentationUrl,omitempty"`
+}
+
+// ComponentMetadata is an interface that describes additional information about the component.
+type ComponentMetadata interface {
+	// Component belongs to the component.
+	Component
+	// ComponentMetadata returns additional information about the component.
+	// This information is used only for introspection and does not affect the application.
+	ComponentMetadata() Metadata
+}
+
 // ComponentComponents is an interface that describes which components the current component depends on.
 type ComponentComponents interface {
 	// Component belongs to the component.
@@ -35,6 +73,66 @@ type ComponentComponents interface {
 	ComponentComponents() ([]Component, error)
 }
 
+// ComponentCondition is an interface that describes whether the component is active in the application.
+type ComponentCondition interface {
+	// Component belongs to the component.
+	Component
+	// ComponentCondition returns true if the component should be active.
+	// This function is called after the configuration is initialized, so you can use the configuration here.
+	// Inactive components and their child components are ignored.
+	ComponentCondition(env Environment) (bool, error)
+}
+
+// ComponentAfter is an interface that describes which components must be initialized before the current component if they are active.
+type ComponentAfter interface {
+	// Component belongs to the component.
+	Component
+	// ComponentAfter returns a list of component identifiers.
+	// Unlike ComponentComponents, these components are not added to the application.
+	// The order is changed only if the component with this identifier is active.
+	ComponentAfter() ([]string, error)
+}
+
+// ComponentVersionConstraints is an interface that describes which versions of other components the current component supports.
+type ComponentVersionConstraints interface {
+	// Component belongs to the component.
+	Component
+	// ComponentVersionConstraints returns version constraints for other components by their identifiers.
+	// For example, map[string]string{"database": ">= 1.2.0, < 2"}.
+	// The constraint is checked only if the component with this identifier is active.
+	ComponentVersionConstraints() (map[string]string, error)
+}
+
+// ComponentConfigDefaults is an interface that describes the default configuration of the component.
+type ComponentConfigDefaults interface {
+	// Component belongs to the component.
+	Component
+	// ComponentConfigDefaults returns the namespace of the component configuration and the default values inside it.
+	// The values are used only if the application configuration does not contain them.
+	// For example, the namespace "server" and the value "addr" are available by the key "server.addr".
+	ComponentConfigDefaults() (namespace string, defaults map[string]any, err error)
+}
+
+// ComponentConfigStatic is an interface that describes the configuration that the component cannot reload.
+type ComponentConfigStatic interface {
+	// Component belongs to the component.
+	Component
+	// ComponentConfigStatic returns prefixes of configuration keys that the component reads only once.
+	// Reloading of the configuration is rejected if the values of these keys change.
+	// An empty prefix means the whole configuration.
+	ComponentConfigStatic() []string
+}
+
+// ComponentConfigSchema is an interface that describes the configuration keys that the component supports.
+type ComponentConfigSchema interface {
+	// Component belongs to the component.
+	Component
+	// ComponentConfigSchema returns the namespace of the component configuration and the keys inside it.
+	// The configuration of the application is checked against the keys of all active components
+	// before the components are initialized.
+	ComponentConfigSchema() (namespace string, keys []*ConfigKey, err error)
+}
+
 // ComponentDependencies is an interface that describes the dependencies of the component.
 type ComponentDependencies interface {
 	// Component belongs to the component.
@@ -61,6 +159,60 @@ type ComponentStop interface {
 	ComponentStop(env Environment, prevErr error) error
 }
 
+// ComponentErrorHandler is an interface that describes the handling of errors that occurred in the component.
+type ComponentErrorHandler interface {
+	// Component belongs to the component.
+	Component
+	// ComponentErrorHandler is called for errors of ComponentInit, ComponentStop and dependencies of the component.
+	// It is called before ApplicationErrorHandler. You can return a new error, the same error or nil to ignore the error.
+	ComponentErrorHandler(err error, appIO ApplicationIO, appMode ApplicationMode) error
+}
+
+// ComponentTimeout is an interface that describes how long the component can be initialized and stopped.
+type ComponentTimeout interface {
+	// Component belongs to the component.
+	Component
+	// ComponentTimeout returns the maximum duration of ComponentInit and ComponentStop.
+	// A zero duration means that the default timeout is used. A negative duration disables the timeout.
+	ComponentTimeout() (initTimeout time.Duration, stopTimeout time.Duration)
+}
+
+// ComponentHealth is an interface that describes the readiness check of the component.
+type ComponentHealth interface {
+	// Component belongs to the component.
+	Component
+	// ComponentHealthCheck returns an error if the component is not ready to work properly.
+	// The context is cancelled when the check timeout is exceeded.
+	ComponentHealthCheck(ctx context.Context) error
+}
+
+// ComponentLiveness is an interface that describes the liveness check of the component.
+type ComponentLiveness interface {
+	// Component belongs to the component.
+	Component
+	// ComponentLivenessCheck returns an error if the component is broken and the application must be restarted.
+	// The context is cancelled when the check timeout is exceeded.
+	ComponentLivenessCheck(ctx context.Context) error
+}
+
+// ComponentRun is an interface that describes the background service of the component.
+type ComponentRun interface {
+	// Component belongs to the component.
+	Component
+	// ComponentRun is called in a separate goroutine after all components are initialized.
+	// It runs at the same time as the application action. The context is cancelled when the application stops.
+	ComponentRun(ctx context.Context) error
+}
+
+// ComponentRestart is an interface that describes when the background service of the component is restarted.
+type ComponentRestart interface {
+	// Component belongs to the component.
+	Component
+	// ComponentRestart returns the restart policy and the delay before the first restart.
+	// The delay is doubled after each consecutive failure.
+	ComponentRestart() (policy RestartPolicy, backoff time.Duration)
+}
+
 // ComponentProvider is an interface that describes a list of active application components.
 // These components are sorted in order of dependencies between components.
 type ComponentProvider interface {
@@ -22,3 +22,22 @@ type ConfigProvider interface {
 	// The value can be modified by the Processor which validates or converts the value.
 	ConfigValue(configKey string, processor Processor) (any, error)
 }
+
+// ConfigKey describes a configuration key that is supported by a component.
+type ConfigKey struct {
+	// Key is the key relative to the namespace of the component. Keys of nested values are joined with a dot.
+	Key string
+	// Type is the expected type of the value: "string", "int", "float", "bool", "duration", "list", "map" or "any".
+	// An empty type means any value.
+	Type string
+	// Default is the value that is used if the configuration does not contain the key. Nil means no default value.
+	Default any
+	// Description is a human-readable description of the key.
+	Description string
+	// Required means that the configuration must contain the key.
+	Required bool
+	// Secret means that the value is masked in configuration dumps and references.
+	Secret bool
+	// Processors validate the value one by one after the type is checked.
+	Processors []Processor
+}
@@ -19,11 +19,18 @@ package driver
 import (
 	"context"
 	"errors"
+	"fmt"
 	"reflect"
 	"runtime"
 	"sync"
+	"time"
 
 	"github.com/componego/componego"
+	"github.com/componego/componego/impl/environment"
+	"github.com/componego/componego/impl/environment/managers/component"
+	"github.com/componego/componego/impl/environment/managers/config"
+	"github.com/componego/componego/impl/environment/managers/dependency"
+	"github.com/componego/componego/impl/events"
 	"github.com/componego/componego/libs/debug"
 	"github.com/componego/componego/libs/xerrors"
 )
@@ -33,6 +40,12 @@ var (
 	ErrPanic = xerrors.New("global panic", "E0100")
 	// ErrUnknownPanic is an error thrown when a panic of an unknown type is received.
 	ErrUnknownPanic = ErrPanic.WithMessage("unknown panic", "E0101")
+	// ErrTimeout is an error thrown when a component does not complete its work in time.
+	ErrTimeout = xerrors.New("component timeout exceeded", "E0110")
+	// ErrInitTimeout is an error thrown when a component is not initialized in time.
+	ErrInitTimeout = ErrTimeout.WithMessage("component initialization timeout exceeded", "E0111")
+	// ErrStopTimeout is an error thrown when a component is not stopped in time.
+	ErrStopTimeout = ErrTimeout.WithMessage("component stop timeout exceeded", "E0112")
 )
 
 // Driver is an interface that describes the driver for starting and controlling the application.
@@ -107,40 +120,290 @@ func (d *driver) CreateEnvironment(ctx context.Context, app componego.Applicatio
 	// We notify environment managers about termination after a normal application shutdown or when an error occurs.
 	cancels := make([]canceller, 3)
 	cancelEnv = sync.OnceValue[error](joinCancels(cancels))
-	if cancels[0], err = configInitializer(env, d.options.Additional); err != nil {
+	configPhase := d.startPhase(env, PhaseConfig, nil)
+	cancels[0], err = configInitializer(env, d.options.Additional)
+	if err = configPhase.finish(err); err != nil {
 		return nil, cancelEnv, err
 	}
-	if cancels[1], err = componentsInitializer(env, d.options.Additional); err != nil {
+	componentsPhase := d.startPhase(env, PhaseComponents, nil)
+	cancels[1], err = componentsInitializer(env, d.options.Additional)
+	if err == nil {
+		// The default configuration of components is available only after the list of active components is received.
+		// The defaults are added here, so they do not depend on the component provider.
+		err = config.ApplyComponentDefaults(env)
+	}
+	if err = componentsPhase.finish(err); err != nil {
 		return nil, cancelEnv, err
 	}
-	if cancels[2], err = dependenciesInitializer(env, d.options.Additional); err != nil {
+	dependenciesPhase := d.startPhase(env, PhaseDependencies, nil)
+	cancels[2], err = dependenciesInitializer(env, d.options.Additional)
+	if err = dependenciesPhase.finish(err); err != nil {
+		// The component that provided the dependency can replace the error, but it cannot ignore it,
+		// because the dependency container cannot be used after the error.
+		if handledErr := handleDependencyError(env, err); handledErr != nil {
+			err = handledErr
+		}
+		return nil, cancelEnv, err
+	}
+	// The configuration is checked against the schema of components before any component reads it.
+	// Processors of the schema can use dependencies, so the check is performed after the dependencies are created.
+	if err = config.ValidateSchema(env); err != nil {
 		return nil, cancelEnv, err
 	}
 	return env, cancelEnv, nil
 }
 
 func (d *driver) runInsideEnvironment(env componego.Environment) (exitCode int, err error) {
+	// The stop phase starts when the first component is stopped and ends after all components are stopped.
+	stopPhase := d.newPhase(env, PhaseStop, nil)
+	defer func() {
+		stopPhase.finish(err)
+	}()
+	if d.options.ParallelComponentInit {
+		return d.runInsideEnvironmentInParallel(env, stopPhase)
+	}
+	initPhase := d.startPhase(env, PhaseInit, nil)
 	for _, component := range env.Components() {
 		// The order in which components are called depends on the dependencies between the components.
 		// Therefore, it is very important to indicate which components your component depends on.
 		if component, ok := component.(componego.ComponentInit); ok {
-			if err = component.ComponentInit(env); err != nil {
-				return componego.ErrorExitCode, err
+			if err = d.initComponent(env, component); err != nil {
+				return componego.ErrorExitCode, initPhase.finish(err)
 			}
 		}
-		if component, ok := component.(componego.ComponentStop); ok {
-			// Stopping a component is guaranteed to occur in the reverse order of component initialization.
-			// noinspection ALL
-			defer func(component componego.ComponentStop) {
-				runtime.Gosched()                         // We switch the runtime so that the waiting goroutines can stop their work.
-				err = ErrorRecoveryOnStop(recover(), err) // We catch the panic that may occur.
-				err = component.ComponentStop(env, err)   // It can handle this error somehow or/and return it to work.
-			}(component) // We support compatibility with older versions of the language.
-		}
+		// Stopping a component is guaranteed to occur in the reverse order of component initialization.
+		// noinspection ALL
+		defer func(component componego.Component) {
+			stopPhase.start()
+			runtime.Gosched()                          // We switch the runtime so that the waiting goroutines can stop their work.
+			err = ErrorRecoveryOnStop(recover(), err)  // We catch the panic that may occur.
+			err = d.stopComponent(env, component, err) // It can handle this error somehow or/and return it to work.
+		}(component) // We support compatibility with older versions of the language.
 	}
+	initPhase.finish(nil)
+	return d.runApplicationAction(env)
+}
+
+func (d *driver) runApplicationAction(env componego.Environment) (exitCode int, err error) {
+	actionPhase := d.startPhase(env, PhaseAction, nil)
+	// Background services run at the same time as the application action.
+	stopServices := startServices(env)
+	defer func() {
+		err = ErrorRecoveryOnStop(recover(), err)
+		err = actionPhase.finish(errors.Join(err, stopServices()))
+	}()
 	return env.Application().ApplicationAction(env, d.options.Additional)
 }
 
+func (d *driver) runInsideEnvironmentInParallel(env componego.Environment, stopPhase *phase) (exitCode int, err error) {
+	components := env.Components()
+	initPhase := d.startPhase(env, PhaseInit, nil)
+	initialized, err := d.initComponentsInParallel(env, components)
+	initPhase.finish(err)
+	for i, component := range components {
+		if !initialized[i] {
+			continue
+		}
+		// Components are stopped in the reverse order of the sorted list of components.
+		// This order is the reverse topological order. Only successfully initialized components are stopped.
+		// noinspection ALL
+		defer func(component componego.Component) {
+			stopPhase.start()
+			runtime.Gosched()
+			err = ErrorRecoveryOnStop(recover(), err)
+			err = d.stopComponent(env, component, err)
+		}(component)
+	}
+	if err != nil {
+		return componego.ErrorExitCode, err
+	}
+	return d.runApplicationAction(env)
+}
+
+// initComponentsInParallel initializes each component as soon as all components it depends on are initialized.
+// A component is not initialized if any of the components it depends on were not initialized.
+func (d *driver) initComponentsInParallel(env componego.Environment, components []componego.Component) ([]bool, error) {
+	previous := getPreviousComponents(env, components)
+	initialized := make([]bool, len(components))
+	errs := make([]error, len(components))
+	done := make([]chan struct{}, len(components))
+	for i := range components {
+		done[i] = make(chan struct{})
+	}
+	waitGroup := &sync.WaitGroup{}
+	waitGroup.Add(len(components))
+	for i, component := range components {
+		go func(i int, component componego.Component) {
+			defer waitGroup.Done()
+			defer close(done[i])
+			for _, j := range previous[i] {
+				<-done[j]
+				if !initialized[j] {
+					return
+				}
+			}
+			defer func() {
+				// Panics cannot leave the goroutine, so we convert them into errors.
+				errs[i] = ErrorRecoveryOnStop(recover(), errs[i])
+			}()
+			if component, ok := component.(componego.ComponentInit); ok {
+				if errs[i] = d.initComponent(env, component); errs[i] != nil {
+					return
+				}
+			}
+			initialized[i] = true
+		}(i, component) // We support compatibility with older versions of the language.
+	}
+	waitGroup.Wait()
+	return initialized, errors.Join(errs...)
+}
+
+// initComponent initializes the component.
+// If the component is not initialized in time, the driver stops waiting for it and returns an error.
+// Errors are passed to the error handler of the component.
+func (d *driver) initComponent(env componego.Environment, component componego.ComponentInit) (err error) {
+	componentPhase := d.startPhase(env, PhaseComponentInit, component)
+	defer func() {
+		// The panic is converted into an error so that the component can handle it.
+		if err = ErrorRecoveryOnStop(recover(), err); err != nil {
+			err = handleComponentError(env, component, StageInit, err)
+		}
+		componentPhase.finish(err)
+	}()
+	timeout, _ := d.getComponentTimeouts(component)
+	if timeout <= 0 {
+		return component.ComponentInit(env)
+	}
+	ok, err := callWithTimeout(timeout, func() error {
+		return component.ComponentInit(env)
+	})
+	if !ok {
+		return ErrInitTimeout.WithMessage(
+			fmt.Sprintf("component '%s' was not initialized in %s", component.ComponentIdentifier(), timeout), "E0113",
+			xerrors.NewOption("componego:driver:component", component),
+			xerrors.NewOption("componego:driver:timeout", timeout),
+		)
+	}
+	return err
+}
+
+// stopComponent stops the component.
+// If the component is not stopped in time, the timeout error is added to the previous error and the shutdown continues.
+// A new error returned by the component is passed to the error handler of the component.
+// Event subscriptions of the component are removed after the component is stopped.
+func (d *driver) stopComponent(env componego.Environment, component componego.Component, prevErr error) error {
+	defer unsubscribeEvents(env, component)
+	stoppableComponent, ok := component.(componego.ComponentStop)
+	if !ok {
+		return prevErr
+	}
+	componentPhase := d.startPhase(env, PhaseComponentStop, component)
+	// The returned error replaces the previous error, so the component can handle errors of other components.
+	err := d.callComponentStop(env, stoppableComponent, prevErr)
+	// Listeners and the error handler receive only errors that the component returned for the first time.
+	prevErrs, newErr := splitStopError(err, prevErr)
+	if newErr == nil {
+		componentPhase.finish(nil)
+		return err
+	}
+	newErr = componentPhase.finish(handleComponentError(env, component, StageStop, newErr))
+	if prevErrs == nil {
+		return newErr
+	} else if newErr == nil {
+		return prevErrs
+	}
+	return errors.Join(prevErrs, newErr)
+}
+
+func (d *driver) callComponentStop(env componego.Environment, component componego.ComponentStop, prevErr error) error {
+	_, timeout := d.getComponentTimeouts(component)
+	if timeout <= 0 {
+		return component.ComponentStop(env, prevErr)
+	}
+	ok, err := callWithTimeout(timeout, func() error {
+		return component.ComponentStop(env, prevErr)
+	})
+	if !ok {
+		return errors.Join(prevErr, ErrStopTimeout.WithMessage(
+			fmt.Sprintf("component '%s' was not stopped in %s", component.ComponentIdentifier(), timeout), "E0114",
+			xerrors.NewOption("componego:driver:component", component),
+			xerrors.NewOption("componego:driver:timeout", timeout),
+		))
+	}
+	return err
+}
+
+// unsubscribeEvents removes event subscriptions of the component.
+// The event bus may be missing if the dependency manager was replaced.
+func unsubscribeEvents(env componego.Environment, component componego.Component) {
+	if bus, err := dependency.Get[events.Bus](env); err == nil {
+		bus.UnsubscribeComponent(component)
+	}
+}
+
+// getComponentTimeouts returns the timeouts of the component. The component timeouts take precedence over the driver options.
+func (d *driver) getComponentTimeouts(component componego.Component) (initTimeout time.Duration, stopTimeout time.Duration) {
+	initTimeout, stopTimeout = d.options.ComponentInitTimeout, d.options.ComponentStopTimeout
+	if component, ok := component.(componego.ComponentTimeout); ok {
+		componentInitTimeout, componentStopTimeout := component.ComponentTimeout()
+		if componentInitTimeout != 0 {
+			initTimeout = componentInitTimeout
+		}
+		if componentStopTimeout != 0 {
+			stopTimeout = componentStopTimeout
+		}
+	}
+	return initTimeout, stopTimeout
+}
+
+// callWithTimeout calls the function in a separate goroutine and waits for the result no longer than the timeout.
+// The first returned value is false if the function did not complete in time. The function continues to run in this case.
+func callWithTimeout(timeout time.Duration, fn func() error) (bool, error) {
+	result := make(chan error, 1)
+	go func() {
+		var err error
+		defer func() {
+			// Panics cannot leave the goroutine, so we convert them into errors.
+			result <- ErrorRecoveryOnStop(recover(), err)
+		}()
+		err = fn()
+	}()
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case err := <-result:
+		return true, err
+	case <-timer.C:
+		return false, nil
+	}
+}
+
+// getPreviousComponents returns indexes of components that must be initialized before each component.
+func getPreviousComponents(env componego.Environment, components []componego.Component) [][]int {
+	previous := make([][]int, len(components))
+	componentProvider, err := environment.GetComponentProvider(env)
+	orderProvider, ok := componentProvider.(component.OrderProvider)
+	if err != nil || !ok {
+		// The order between components is unknown, so each component waits for the previous component.
+		for i := 1; i < len(components); i++ {
+			previous[i] = []int{i - 1}
+		}
+		return previous
+	}
+	indexes := make(map[string]int, len(components))
+	for i, component := range components {
+		indexes[component.ComponentIdentifier()] = i
+	}
+	for i, component := range components {
+		for _, identifier := range orderProvider.ComponentPrevious(component.ComponentIdentifier()) {
+			if j, ok := indexes[identifier]; ok && j < i {
+				previous[i] = append(previous[i], j)
+			}
+		}
+	}
+	return previous
+}
+
 // ErrorRecoveryOnStop returns an error after panic recovery.
 func ErrorRecoveryOnStop(recover any, prevErr error) (newErr error) {
 	if recover == nil {
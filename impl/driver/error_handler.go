@@ -0,0 +1,151 @@
+/*
+Copyright 2024-present Volodymyr Konstanchuk and contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"errors"
+	"reflect"
+
+	"github.com/componego/componego"
+	"github.com/componego/componego/impl/environment/managers/dependency"
+	"github.com/componego/componego/libs/xerrors"
+)
+
+const (
+	// StageInit is the stage of the component initialization.
+	StageInit = "init"
+	// StageStop is the stage of the component stopping.
+	StageStop = "stop"
+	// StageDependencies is the stage of the creation of the component dependencies.
+	StageDependencies = "dependencies"
+)
+
+// ErrComponent is an error that occurred inside the component.
+// The component and the stage are available in the error options.
+var ErrComponent = xerrors.New("error inside the component", "E0130")
+
+// GetErrorComponent returns the component in which the error occurred and the stage of the error.
+func GetErrorComponent(err error) (componego.Component, string, bool) {
+	for _, err := range xerrors.UnwrapAll(err) {
+		// noinspection ALL
+		xErr, ok := err.(xerrors.XError) //nolint:errorlint
+		if !ok || xErr.ErrorCode() != "E0131" {
+			continue
+		}
+		var (
+			component componego.Component
+			stage     string
+		)
+		for _, option := range xErr.ErrorOptions() {
+			switch option.Key() {
+			case "componego:driver:component":
+				component, _ = option.Value().(componego.Component)
+			case "componego:driver:stage":
+				stage, _ = option.Value().(string)
+			}
+		}
+		return component, stage, component != nil
+	}
+	return nil, "", false
+}
+
+// handleComponentError links the error to the component and passes it to the error handler of the component.
+func handleComponentError(env componego.Environment, component componego.Component, stage string, err error) error {
+	err = ErrComponent.WithError(err, "E0131",
+		xerrors.NewOption("componego:driver:component", component),
+		xerrors.NewOption("componego:driver:stage", stage),
+	)
+	if component, ok := component.(componego.ComponentErrorHandler); ok {
+		return component.ComponentErrorHandler(err, env.ApplicationIO(), env.ApplicationMode())
+	}
+	return err
+}
+
+// handleDependencyError finds the component that provided the dependency that caused the error.
+// The error is returned unchanged if the dependency does not belong to any component.
+func handleDependencyError(env componego.Environment, err error) error {
+	var requestedType reflect.Type
+	for _, err := range xerrors.UnwrapAll(err) {
+		// noinspection ALL
+		xErr, ok := err.(xerrors.XError) //nolint:errorlint
+		if !ok {
+			continue
+		}
+		for _, option := range xErr.ErrorOptions() {
+			if option.Key() == "componego:dependency:container:requestedType" {
+				requestedType, _ = option.Value().(reflect.Type)
+				break
+			}
+		}
+		if requestedType != nil {
+			break
+		}
+	}
+	if requestedType == nil {
+		return err
+	}
+	// The dependency manager remembers which component provided each type when it collected the dependencies.
+	typeProvider, ok := env.DependencyInvoker().(dependency.TypeProvider)
+	if !ok {
+		return err
+	}
+	if component, ok := typeProvider.TypeComponent(requestedType); ok {
+		return handleComponentError(env, component, StageDependencies, err)
+	}
+	return err
+}
+
+// splitStopError splits the error returned by ComponentStop into the part that contains the previous error
+// and the errors that the component returned for the first time.
+// Components usually join their errors with the previous error, so only joined errors are split.
+func splitStopError(err error, prevErr error) (error, error) {
+	if err == nil {
+		return nil, nil
+	} else if prevErr == nil || !errors.Is(err, prevErr) {
+		return nil, err
+	} else if errors.Is(prevErr, err) {
+		// The error is the previous error or a part of it.
+		return err, nil
+	}
+	// noinspection ALL
+	joinedErr, ok := err.(interface{ Unwrap() []error }) //nolint:errorlint
+	// noinspection ALL
+	if _, isXErr := err.(xerrors.XError); !ok || isXErr { //nolint:errorlint
+		// The component wrapped the previous error in its own error.
+		return err, nil
+	}
+	prevErrs := make([]error, 0)
+	newErrs := make([]error, 0)
+	for _, item := range joinedErr.Unwrap() {
+		prevItem, newItem := splitStopError(item, prevErr)
+		if prevItem != nil {
+			prevErrs = append(prevErrs, prevItem)
+		}
+		if newItem != nil {
+			newErrs = append(newErrs, newItem)
+		}
+	}
+	return oneError(prevErrs), oneError(newErrs)
+}
+
+// oneError returns the only error of the list or the joined errors.
+func oneError(errs []error) error {
+	if len(errs) == 1 {
+		return errs[0]
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,133 @@
+/*
+Copyright 2024-present Volodymyr Konstanchuk and contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"time"
+
+	"github.com/componego/componego"
+)
+
+// Phase is a step of the application lifecycle.
+type Phase string
+
+const (
+	PhaseConfig        Phase = "config"
+	PhaseComponents    Phase = "components"
+	PhaseDependencies  Phase = "dependencies"
+	PhaseInit          Phase = "init"
+	PhaseAction        Phase = "action"
+	PhaseStop          Phase = "stop"
+	PhaseComponentInit Phase = "component:init"
+	PhaseComponentStop Phase = "component:stop"
+)
+
+// LifecycleListener is an interface that describes an observer of the application lifecycle.
+// Listeners are called in the order in which they are passed to the driver.
+// They must be safe for concurrent use because components can be initialized in parallel.
+type LifecycleListener interface {
+	// BeforePhase is called before the phase starts.
+	BeforePhase(event *LifecycleEvent)
+	// AfterPhase is called after the phase ends. The event contains the duration and the error of the phase.
+	AfterPhase(event *LifecycleEvent)
+}
+
+// LifecycleEvent contains information about a phase of the application lifecycle.
+type LifecycleEvent struct {
+	Phase       Phase
+	Environment componego.Environment
+	// Component is only set for the component phases.
+	Component componego.Component
+	StartedAt time.Time
+	// Duration and Err are only set after the phase ends.
+	Duration time.Duration
+	Err      error
+}
+
+// LifecycleFuncs allows functions to be used as a lifecycle listener. Any of the functions can be nil.
+type LifecycleFuncs struct {
+	Before func(event *LifecycleEvent)
+	After  func(event *LifecycleEvent)
+}
+
+// BeforePhase belongs to interface LifecycleListener.
+func (l *LifecycleFuncs) BeforePhase(event *LifecycleEvent) {
+	if l.Before != nil {
+		l.Before(event)
+	}
+}
+
+// AfterPhase belongs to interface LifecycleListener.
+func (l *LifecycleFuncs) AfterPhase(event *LifecycleEvent) {
+	if l.After != nil {
+		l.After(event)
+	}
+}
+
+// phase notifies listeners about the start and the end of a phase.
+type phase struct {
+	listeners []LifecycleListener
+	event     *LifecycleEvent
+	started   bool
+}
+
+func (d *driver) newPhase(env componego.Environment, name Phase, component componego.Component) *phase {
+	return &phase{
+		listeners: d.options.LifecycleListeners,
+		event: &LifecycleEvent{
+			Phase:       name,
+			Environment: env,
+			Component:   component,
+		},
+	}
+}
+
+func (d *driver) startPhase(env componego.Environment, name Phase, component componego.Component) *phase {
+	p := d.newPhase(env, name, component)
+	p.start()
+	return p
+}
+
+// start notifies listeners that the phase has started. Repeated calls are ignored.
+func (p *phase) start() {
+	if p.started {
+		return
+	}
+	p.started = true
+	p.event.StartedAt = time.Now()
+	for _, listener := range p.listeners {
+		// Each listener receives its own copy of the event.
+		event := *p.event
+		listener.BeforePhase(&event)
+	}
+}
+
+// finish notifies listeners that the phase has ended and returns the error unchanged.
+func (p *phase) finish(err error) error {
+	p.start()
+	p.event.Duration = time.Since(p.event.StartedAt)
+	p.event.Err = err
+	for _, listener := range p.listeners {
+		event := *p.event
+		listener.AfterPhase(&event)
+	}
+	return err
+}
+
+var (
+	_ LifecycleListener = (*LifecycleFuncs)(nil)
+)
@@ -19,6 +19,7 @@ package driver
 import (
 	"context"
 	"os"
+	"time"
 
 	"github.com/componego/componego"
 	"github.com/componego/componego/impl/application"
@@ -50,6 +51,18 @@ type Options struct {
 	) componego.Environment
 	AppIO      componego.ApplicationIO
 	Additional any
+	// DefaultDependencies are added to the dependencies of every application launched by the driver.
+	DefaultDependencies []dependency.DefaultDependency
+	// ComponentConflictPolicy specifies what happens when several components have the same identifier.
+	ComponentConflictPolicy component.ConflictPolicy
+	// ParallelComponentInit allows independent components to be initialized at the same time.
+	ParallelComponentInit bool
+	// ComponentInitTimeout is the default maximum duration of component initialization. Zero means no timeout.
+	ComponentInitTimeout time.Duration
+	// ComponentStopTimeout is the default maximum duration of component stopping. Zero means no timeout.
+	ComponentStopTimeout time.Duration
+	// LifecycleListeners are notified before and after each phase of the application lifecycle.
+	LifecycleListeners []LifecycleListener
 }
 
 func Configure(options *Options) *Options {
@@ -60,10 +73,10 @@ func Configure(options *Options) *Options {
 		options.ConfigProviderFactory = newConfigFactory
 	}
 	if options.ComponentProviderFactory == nil {
-		options.ComponentProviderFactory = newComponentProviderFactory
+		options.ComponentProviderFactory = newComponentProviderFactory(options)
 	}
 	if options.DependencyInvokerFactory == nil {
-		options.DependencyInvokerFactory = newDependencyInvokerFactory
+		options.DependencyInvokerFactory = newDependencyInvokerFactory(options)
 	}
 	if options.EnvironmentFactory == nil {
 		options.EnvironmentFactory = environment.New
@@ -79,41 +92,49 @@ func Configure(options *Options) *Options {
 	return options
 }
 
-func newComponentProviderFactory() (componego.ComponentProvider, initializer) {
-	manager, initializer := component.NewManager()
-	return manager, func(env componego.Environment, _ any) (canceller, error) {
-		components, err := component.ExtractComponents(env.Application())
-		if err != nil {
-			return nil, err
+func newComponentProviderFactory(options *Options) func() (componego.ComponentProvider, initializer) {
+	return func() (componego.ComponentProvider, initializer) {
+		manager, initializer := component.NewManagerWithPolicy(options.ComponentConflictPolicy)
+		return manager, func(env componego.Environment, _ any) (canceller, error) {
+			components, err := component.ExtractComponents(env.Application())
+			if err != nil {
+				return nil, err
+			}
+			return nil, initializer(env, components)
 		}
-		return nil, initializer(components)
 	}
 }
 
-func newDependencyInvokerFactory() (componego.DependencyInvoker, initializer) {
-	manager, initializer := dependency.NewManager()
-	return manager, func(env componego.Environment, _ any) (canceller, error) {
-		dependencies, err := dependency.ExtractDependencies(env)
-		if err != nil {
-			return nil, err
+func newDependencyInvokerFactory(options *Options) func() (componego.DependencyInvoker, initializer) {
+	return func() (componego.DependencyInvoker, initializer) {
+		manager, initializer := dependency.NewManagerWithDependencies()
+		return manager, func(env componego.Environment, _ any) (canceller, error) {
+			dependencies, err := dependency.CollectDependencies(env, options.DefaultDependencies...)
+			if err != nil {
+				return nil, err
+			}
+			containerInstance, containerInitializer := container.New(len(dependencies.List))
+			// There may be a recursive call to the container through the dependency manager
+			// during the initialization of dependencies inside the container.
+			if err = initializer(containerInstance, dependencies); err != nil {
+				return nil, err
+			}
+			return containerInitializer(dependencies.List)
 		}
-		containerInstance, containerInitializer := container.New(len(dependencies))
-		// There may be a recursive call to the container through the dependency manager
-		// during the initialization of dependencies inside the container.
-		if err = initializer(containerInstance); err != nil {
-			return nil, err
-		}
-		return containerInitializer(dependencies)
 	}
 }
 
 func newConfigFactory() (componego.ConfigProvider, initializer) {
-	manager, initializer := config.NewManager()
+	manager, initializer := config.NewManagerWithSettings()
 	return manager, func(env componego.Environment, options any) (canceller, error) {
-		parsedConfig, err := config.ParseConfig(env, options)
+		settings, err := config.ParseSettings(env, options)
 		if err != nil {
 			return nil, err
 		}
-		return nil, initializer(env, parsedConfig)
+		if err = initializer(env, settings); err != nil {
+			return nil, err
+		}
+		// The configuration can be reloaded only if the application allows it.
+		return nil, config.EnableApplicationReload(env, options)
 	}
 }
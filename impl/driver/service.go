@@ -0,0 +1,112 @@
+/*
+Copyright 2024-present Volodymyr Konstanchuk and contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package driver
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/componego/componego"
+	"github.com/componego/componego/impl/environment/managers/component"
+	"github.com/componego/componego/libs/xerrors"
+)
+
+const (
+	defaultRestartBackoff = time.Second
+	maxRestartBackoff     = time.Minute
+)
+
+// ErrService is an error thrown when a background service of the component fails.
+var ErrService = xerrors.New("background service of the component failed", "E0120")
+
+// startServices runs background services of all components and returns a function that stops them.
+// The returned function waits for all services to stop and returns their errors.
+func startServices(env componego.Environment) func() error {
+	ctx, cancel := context.WithCancel(env.GetContext())
+	waitGroup := &sync.WaitGroup{}
+	components := env.Components()
+	errs := make([]error, len(components))
+	for i, item := range components {
+		if service, ok := component.Implements[componego.ComponentRun](item); ok {
+			waitGroup.Add(1)
+			go func(i int, component componego.ComponentRun) {
+				defer waitGroup.Done()
+				errs[i] = superviseService(ctx, component)
+			}(i, service) // We support compatibility with older versions of the language.
+		}
+	}
+	return func() error {
+		cancel()
+		waitGroup.Wait()
+		return errors.Join(errs...)
+	}
+}
+
+// superviseService runs the background service of the component and restarts it according to its restart policy.
+// It returns the error of the last run of the service.
+func superviseService(ctx context.Context, component componego.ComponentRun) error {
+	policy, backoff := componego.RestartNever, time.Duration(0)
+	if component, ok := component.(componego.ComponentRestart); ok {
+		policy, backoff = component.ComponentRestart()
+	}
+	if backoff <= 0 {
+		backoff = defaultRestartBackoff
+	}
+	delay := backoff
+	for restarts := 0; ; restarts++ {
+		err := runService(ctx, component)
+		if ctx.Err() != nil {
+			// The service was stopped by the application, so the cancellation is not an error.
+			if err == nil || errors.Is(err, ctx.Err()) {
+				return nil
+			}
+		}
+		if err != nil {
+			err = ErrService.WithError(err, "E0121",
+				xerrors.NewOption("componego:driver:component", component),
+				xerrors.NewOption("componego:driver:restarts", restarts),
+			)
+		}
+		if ctx.Err() != nil || policy == componego.RestartNever || (policy == componego.RestartOnFailure && err == nil) {
+			return err
+		}
+		if err == nil {
+			// The delay is increased only for consecutive failures.
+			delay = backoff
+		}
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+		if err != nil {
+			delay = min(delay*2, maxRestartBackoff)
+		}
+	}
+}
+
+func runService(ctx context.Context, component componego.ComponentRun) (err error) {
+	defer func() {
+		// Panics cannot leave the goroutine, so we convert them into errors.
+		err = ErrorRecoveryOnStop(recover(), err)
+	}()
+	return component.ComponentRun(ctx)
+}
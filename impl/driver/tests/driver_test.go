@@ -17,10 +17,19 @@ limitations under the License.
 package tests
 
 import (
+	"bytes"
+	"context"
 	"errors"
+	"runtime"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
+	"github.com/componego/componego"
+	"github.com/componego/componego/impl/application"
 	"github.com/componego/componego/impl/driver"
+	"github.com/componego/componego/impl/environment/managers/component"
 	"github.com/componego/componego/internal/testing/require"
 	"github.com/componego/componego/internal/testing/types"
 )
@@ -99,3 +108,687 @@ func TestErrorRecoveryOnStop(t *testing.T) {
 		require.ErrorIs(t, resultErr, prevErr)
 	})
 }
+
+func TestParallelComponentInit(t *testing.T) {
+	newDriver := func() driver.Driver {
+		return driver.New(&driver.Options{
+			AppIO:                 application.NewIO(nil, &bytes.Buffer{}, &bytes.Buffer{}),
+			ParallelComponentInit: true,
+		})
+	}
+	mutex := &sync.Mutex{}
+	calls := make([]string, 0, 10)
+	logCall := func(call string) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		calls = append(calls, call)
+	}
+	newComponent := func(identifier string, init func() error, children ...componego.Component) componego.Component {
+		factory := component.NewFactory(identifier, "0.0.1")
+		factory.SetComponentComponents(func() ([]componego.Component, error) {
+			return children, nil
+		})
+		factory.SetComponentInit(func(_ componego.Environment) error {
+			logCall("init " + identifier)
+			return init()
+		})
+		factory.SetComponentStop(func(_ componego.Environment, prevErr error) error {
+			logCall("stop " + identifier)
+			return prevErr
+		})
+		return factory.Build()
+	}
+	newApp := func(components ...componego.Component) componego.Application {
+		appFactory := application.NewFactory("Application Parallel Test")
+		appFactory.SetApplicationComponents(func() ([]componego.Component, error) {
+			return components, nil
+		})
+		appFactory.SetApplicationAction(func(_ componego.Environment, _ any) (int, error) {
+			logCall("action")
+			return componego.SuccessExitCode, nil
+		})
+		return appFactory.Build()
+	}
+	noError := func() error {
+		return nil
+	}
+
+	t.Run("independent components are initialized at the same time", func(t *testing.T) {
+		calls = calls[:0]
+		started := make(chan struct{}, 2)
+		// Each component waits until the other component starts initialization.
+		waitForOther := func() error {
+			started <- struct{}{}
+			timer := time.NewTimer(5 * time.Second)
+			defer timer.Stop()
+			for {
+				select {
+				case <-timer.C:
+					return errors.New("components are not initialized at the same time")
+				default:
+				}
+				if len(started) == 2 {
+					return nil
+				}
+				runtime.Gosched()
+			}
+		}
+		app := newApp(
+			newComponent("component 1", waitForOther, newComponent("component 2", noError)),
+			newComponent("component 3", waitForOther),
+		)
+		exitCode, err := newDriver().RunApplication(context.Background(), app, componego.TestMode)
+		require.NoError(t, err)
+		require.Equal(t, componego.SuccessExitCode, exitCode)
+		require.Len(t, calls, 7)
+		require.Equal(t, []string{
+			"action",
+			"stop component 3",
+			"stop component 1",
+			"stop component 2",
+		}, calls[3:])
+	})
+
+	t.Run("child components are initialized first", func(t *testing.T) {
+		calls = calls[:0]
+		app := newApp(newComponent("component 1", noError, newComponent("component 2", noError, newComponent("component 3", noError))))
+		exitCode, err := newDriver().RunApplication(context.Background(), app, componego.TestMode)
+		require.NoError(t, err)
+		require.Equal(t, componego.SuccessExitCode, exitCode)
+		require.Equal(t, []string{
+			"init component 3",
+			"init component 2",
+			"init component 1",
+			"action",
+			"stop component 1",
+			"stop component 2",
+			"stop component 3",
+		}, calls)
+	})
+
+	t.Run("only initialized components are stopped", func(t *testing.T) {
+		calls = calls[:0]
+		customErr := errors.New("custom error")
+		failedComponent := newComponent("component 2", func() error {
+			return customErr
+		})
+		app := newApp(
+			newComponent("component 1", noError, failedComponent),
+			newComponent("component 3", noError),
+		)
+		exitCode, err := newDriver().RunApplication(context.Background(), app, componego.TestMode)
+		require.ErrorIs(t, err, customErr)
+		require.Equal(t, componego.ErrorExitCode, exitCode)
+		require.ElementsMatch(t, []string{
+			"init component 2",
+			"init component 3",
+			"stop component 3",
+		}, calls)
+	})
+
+	t.Run("panic during initialization", func(t *testing.T) {
+		calls = calls[:0]
+		app := newApp(
+			newComponent("component 1", func() error {
+				panic("panic occurred")
+			}),
+			newComponent("component 2", noError),
+		)
+		require.NotPanics(t, func() {
+			exitCode, err := newDriver().RunApplication(context.Background(), app, componego.TestMode)
+			require.ErrorIs(t, err, driver.ErrPanic)
+			require.ErrorContains(t, err, "panic occurred")
+			require.Equal(t, componego.ErrorExitCode, exitCode)
+		})
+		require.ElementsMatch(t, []string{
+			"init component 1",
+			"init component 2",
+			"stop component 2",
+		}, calls)
+	})
+}
+
+func TestComponentTimeouts(t *testing.T) {
+	newApp := func(components ...componego.Component) componego.Application {
+		appFactory := application.NewFactory("Application Timeout Test")
+		appFactory.SetApplicationComponents(func() ([]componego.Component, error) {
+			return components, nil
+		})
+		appFactory.SetApplicationAction(func(_ componego.Environment, _ any) (int, error) {
+			return componego.SuccessExitCode, nil
+		})
+		return appFactory.Build()
+	}
+	newDriver := func(initTimeout time.Duration, stopTimeout time.Duration) driver.Driver {
+		return driver.New(&driver.Options{
+			AppIO:                application.NewIO(nil, &bytes.Buffer{}, &bytes.Buffer{}),
+			ComponentInitTimeout: initTimeout,
+			ComponentStopTimeout: stopTimeout,
+		})
+	}
+
+	t.Run("default init timeout", func(t *testing.T) {
+		release := make(chan struct{})
+		defer close(release)
+		isStopped := false
+		componentFactory := component.NewFactory("component", "0.0.1")
+		componentFactory.SetComponentInit(func(_ componego.Environment) error {
+			<-release
+			return nil
+		})
+		componentFactory.SetComponentStop(func(_ componego.Environment, prevErr error) error {
+			isStopped = true
+			return prevErr
+		})
+		exitCode, err := newDriver(10*time.Millisecond, 0).RunApplication(context.Background(), newApp(componentFactory.Build()), componego.TestMode)
+		require.Equal(t, componego.ErrorExitCode, exitCode)
+		require.ErrorIs(t, err, driver.ErrInitTimeout)
+		require.ErrorIs(t, err, driver.ErrTimeout)
+		require.ErrorContains(t, err, "component 'component' was not initialized in 10ms")
+		require.False(t, isStopped)
+	})
+
+	t.Run("component timeout takes precedence", func(t *testing.T) {
+		componentFactory := component.NewFactory("component", "0.0.1")
+		componentFactory.SetComponentInit(func(_ componego.Environment) error {
+			time.Sleep(20 * time.Millisecond)
+			return nil
+		})
+		componentFactory.SetComponentTimeout(func() (time.Duration, time.Duration) {
+			return -1, 0
+		})
+		exitCode, err := newDriver(time.Millisecond, 0).RunApplication(context.Background(), newApp(componentFactory.Build()), componego.TestMode)
+		require.NoError(t, err)
+		require.Equal(t, componego.SuccessExitCode, exitCode)
+	})
+
+	t.Run("panic with timeout", func(t *testing.T) {
+		componentFactory := component.NewFactory("component", "0.0.1")
+		componentFactory.SetComponentInit(func(_ componego.Environment) error {
+			panic("panic occurred")
+		})
+		require.NotPanics(t, func() {
+			exitCode, err := newDriver(time.Second, 0).RunApplication(context.Background(), newApp(componentFactory.Build()), componego.TestMode)
+			require.Equal(t, componego.ErrorExitCode, exitCode)
+			require.ErrorIs(t, err, driver.ErrPanic)
+			require.NotErrorIs(t, err, driver.ErrTimeout)
+		})
+	})
+
+	t.Run("shutdown continues after stop timeout", func(t *testing.T) {
+		release := make(chan struct{})
+		defer close(release)
+		customErr := errors.New("custom error")
+		isStopped := false
+		component1Factory := component.NewFactory("component 1", "0.0.1")
+		component1Factory.SetComponentStop(func(_ componego.Environment, prevErr error) error {
+			isStopped = true
+			return errors.Join(prevErr, customErr)
+		})
+		component2Factory := component.NewFactory("component 2", "0.0.1")
+		component2Factory.SetComponentStop(func(_ componego.Environment, prevErr error) error {
+			<-release
+			return prevErr
+		})
+		component2Factory.SetComponentTimeout(func() (time.Duration, time.Duration) {
+			return 0, 10 * time.Millisecond
+		})
+		app := newApp(component1Factory.Build(), component2Factory.Build())
+		exitCode, err := newDriver(0, time.Second).RunApplication(context.Background(), app, componego.TestMode)
+		require.Equal(t, componego.ErrorExitCode, exitCode)
+		require.ErrorIs(t, err, driver.ErrStopTimeout)
+		require.ErrorContains(t, err, "component 'component 2' was not stopped in 10ms")
+		require.ErrorIs(t, err, customErr)
+		require.True(t, isStopped)
+	})
+}
+
+func TestComponentServices(t *testing.T) {
+	customErr := errors.New("custom error")
+	newService := func(policy componego.RestartPolicy, run func(ctx context.Context) error) componego.Component {
+		factory := component.NewFactory("service", "0.0.1")
+		factory.SetComponentRun(run)
+		factory.SetComponentRestart(func() (componego.RestartPolicy, time.Duration) {
+			return policy, time.Millisecond
+		})
+		return factory.Build()
+	}
+	runApp := func(action func() error, components ...componego.Component) (int, error) {
+		appFactory := application.NewFactory("Application Service Test")
+		appFactory.SetApplicationComponents(func() ([]componego.Component, error) {
+			return components, nil
+		})
+		appFactory.SetApplicationAction(func(_ componego.Environment, _ any) (int, error) {
+			return componego.SuccessExitCode, action()
+		})
+		d := driver.New(&driver.Options{
+			AppIO: application.NewIO(nil, &bytes.Buffer{}, &bytes.Buffer{}),
+		})
+		return d.RunApplication(context.Background(), appFactory.Build(), componego.TestMode)
+	}
+	waitFor := func(condition func() bool) error {
+		timer := time.NewTimer(5 * time.Second)
+		defer timer.Stop()
+		for !condition() {
+			select {
+			case <-timer.C:
+				return errors.New("condition is not met")
+			case <-time.After(time.Millisecond):
+			}
+		}
+		return nil
+	}
+
+	t.Run("service is cancelled on shutdown", func(t *testing.T) {
+		mutex := &sync.Mutex{}
+		calls := make([]string, 0, 3)
+		logCall := func(call string) {
+			mutex.Lock()
+			defer mutex.Unlock()
+			calls = append(calls, call)
+		}
+		started := make(chan struct{})
+		service := newService(componego.RestartNever, func(ctx context.Context) error {
+			close(started)
+			<-ctx.Done()
+			logCall("service stopped")
+			return ctx.Err()
+		})
+		componentFactory := component.NewFactory("component", "0.0.1")
+		componentFactory.SetComponentStop(func(_ componego.Environment, prevErr error) error {
+			logCall("component stopped")
+			return prevErr
+		})
+		exitCode, err := runApp(func() error {
+			<-started
+			logCall("action")
+			return nil
+		}, componentFactory.Build(), service)
+		require.NoError(t, err)
+		require.Equal(t, componego.SuccessExitCode, exitCode)
+		require.Equal(t, []string{"action", "service stopped", "component stopped"}, calls)
+	})
+
+	t.Run("service error is joined into the application error", func(t *testing.T) {
+		done := make(chan struct{})
+		service := newService(componego.RestartNever, func(_ context.Context) error {
+			defer close(done)
+			return customErr
+		})
+		exitCode, err := runApp(func() error {
+			<-done
+			return nil
+		}, service)
+		require.ErrorIs(t, err, driver.ErrService)
+		require.ErrorIs(t, err, customErr)
+		require.Equal(t, componego.ErrorExitCode, exitCode)
+	})
+
+	t.Run("restart on failure", func(t *testing.T) {
+		runs := int32(0)
+		service := newService(componego.RestartOnFailure, func(ctx context.Context) error {
+			if atomic.AddInt32(&runs, 1) < 3 {
+				return customErr
+			}
+			<-ctx.Done()
+			return nil
+		})
+		exitCode, err := runApp(func() error {
+			return waitFor(func() bool {
+				return atomic.LoadInt32(&runs) == 3
+			})
+		}, service)
+		require.NoError(t, err)
+		require.Equal(t, componego.SuccessExitCode, exitCode)
+		require.Equal(t, int32(3), atomic.LoadInt32(&runs))
+	})
+
+	t.Run("restart always", func(t *testing.T) {
+		runs := int32(0)
+		service := newService(componego.RestartAlways, func(_ context.Context) error {
+			atomic.AddInt32(&runs, 1)
+			return nil
+		})
+		exitCode, err := runApp(func() error {
+			return waitFor(func() bool {
+				return atomic.LoadInt32(&runs) >= 3
+			})
+		}, service)
+		require.NoError(t, err)
+		require.Equal(t, componego.SuccessExitCode, exitCode)
+	})
+
+	t.Run("component without service", func(t *testing.T) {
+		factory := component.NewFactory("component", "0.0.1")
+		factory.SetComponentRestart(func() (componego.RestartPolicy, time.Duration) {
+			return componego.RestartAlways, time.Millisecond
+		})
+		exitCode, err := runApp(func() error {
+			return nil
+		}, factory.Build())
+		require.NoError(t, err)
+		require.Equal(t, componego.SuccessExitCode, exitCode)
+	})
+
+	t.Run("panic inside service", func(t *testing.T) {
+		done := make(chan struct{})
+		service := newService(componego.RestartNever, func(_ context.Context) error {
+			defer close(done)
+			panic("panic occurred")
+		})
+		require.NotPanics(t, func() {
+			exitCode, err := runApp(func() error {
+				<-done
+				return nil
+			}, service)
+			require.ErrorIs(t, err, driver.ErrService)
+			require.ErrorIs(t, err, driver.ErrPanic)
+			require.Equal(t, componego.ErrorExitCode, exitCode)
+		})
+	})
+}
+
+func TestComponentErrorHandler(t *testing.T) {
+	customErr := errors.New("custom error")
+	friendlyErr := errors.New("friendly error")
+	runApp := func(components ...componego.Component) (int, error) {
+		appFactory := application.NewFactory("Application Error Handler Test")
+		appFactory.SetApplicationComponents(func() ([]componego.Component, error) {
+			return components, nil
+		})
+		appFactory.SetApplicationAction(func(_ componego.Environment, _ any) (int, error) {
+			return componego.SuccessExitCode, nil
+		})
+		d := driver.New(&driver.Options{
+			AppIO: application.NewIO(nil, &bytes.Buffer{}, &bytes.Buffer{}),
+		})
+		return d.RunApplication(context.Background(), appFactory.Build(), componego.TestMode)
+	}
+	newComponent := func(factory component.Factory, handler func(err error) error) componego.Component {
+		factory.SetComponentErrorHandler(func(err error, _ componego.ApplicationIO, _ componego.ApplicationMode) error {
+			return handler(err)
+		})
+		return factory.Build()
+	}
+
+	t.Run("errors are linked to components", func(t *testing.T) {
+		componentFactory := component.NewFactory("component", "0.0.1")
+		componentFactory.SetComponentInit(func(_ componego.Environment) error {
+			return customErr
+		})
+		exitCode, err := runApp(componentFactory.Build())
+		require.Equal(t, componego.ErrorExitCode, exitCode)
+		require.ErrorIs(t, err, customErr)
+		require.ErrorIs(t, err, driver.ErrComponent)
+		errComponent, stage, ok := driver.GetErrorComponent(err)
+		require.True(t, ok)
+		require.Equal(t, "component", errComponent.ComponentIdentifier())
+		require.Equal(t, driver.StageInit, stage)
+		_, _, ok = driver.GetErrorComponent(customErr)
+		require.False(t, ok)
+	})
+
+	t.Run("init error is replaced", func(t *testing.T) {
+		componentFactory := component.NewFactory("component", "0.0.1")
+		componentFactory.SetComponentInit(func(_ componego.Environment) error {
+			return customErr
+		})
+		exitCode, err := runApp(newComponent(componentFactory, func(err error) error {
+			_, stage, _ := driver.GetErrorComponent(err)
+			require.Equal(t, driver.StageInit, stage)
+			require.ErrorIs(t, err, customErr)
+			return friendlyErr
+		}))
+		require.Equal(t, componego.ErrorExitCode, exitCode)
+		require.ErrorIs(t, err, friendlyErr)
+		require.NotErrorIs(t, err, customErr)
+	})
+
+	t.Run("init panic is handled", func(t *testing.T) {
+		isStopped := false
+		componentFactory := component.NewFactory("component", "0.0.1")
+		componentFactory.SetComponentInit(func(_ componego.Environment) error {
+			panic("panic occurred")
+		})
+		componentFactory.SetComponentStop(func(_ componego.Environment, prevErr error) error {
+			isStopped = true
+			return prevErr
+		})
+		exitCode, err := runApp(newComponent(componentFactory, func(err error) error {
+			require.ErrorIs(t, err, driver.ErrPanic)
+			return nil
+		}))
+		require.NoError(t, err)
+		require.Equal(t, componego.SuccessExitCode, exitCode)
+		// The component is considered to be initialized if the error is ignored.
+		require.True(t, isStopped)
+	})
+
+	t.Run("expected stop error is ignored", func(t *testing.T) {
+		component1Factory := component.NewFactory("component 1", "0.0.1")
+		component1Factory.SetComponentStop(func(_ componego.Environment, prevErr error) error {
+			return errors.Join(prevErr, customErr)
+		})
+		component2Factory := component.NewFactory("component 2", "0.0.1")
+		component2Factory.SetComponentStop(func(_ componego.Environment, prevErr error) error {
+			require.NoError(t, prevErr)
+			return context.Canceled
+		})
+		exitCode, err := runApp(component1Factory.Build(), newComponent(component2Factory, func(err error) error {
+			_, stage, _ := driver.GetErrorComponent(err)
+			require.Equal(t, driver.StageStop, stage)
+			if errors.Is(err, context.Canceled) {
+				return nil
+			}
+			return err
+		}))
+		require.Equal(t, componego.ErrorExitCode, exitCode)
+		require.ErrorIs(t, err, customErr)
+		require.NotErrorIs(t, err, context.Canceled)
+		errComponent, stage, ok := driver.GetErrorComponent(err)
+		require.True(t, ok)
+		require.Equal(t, "component 1", errComponent.ComponentIdentifier())
+		require.Equal(t, driver.StageStop, stage)
+	})
+
+	t.Run("previous error is not passed to the error handler", func(t *testing.T) {
+		appErr := errors.New("application error")
+		component1Factory := component.NewFactory("component 1", "0.0.1")
+		component1Factory.SetComponentStop(func(_ componego.Environment, prevErr error) error {
+			require.ErrorIs(t, prevErr, appErr)
+			require.NotErrorIs(t, prevErr, customErr)
+			return prevErr
+		})
+		component2Factory := component.NewFactory("component 2", "0.0.1")
+		component2Factory.SetComponentStop(func(_ componego.Environment, prevErr error) error {
+			return errors.Join(prevErr, customErr)
+		})
+		isHandled := false
+		appFactory := application.NewFactory("Application Error Handler Test")
+		appFactory.SetApplicationComponents(func() ([]componego.Component, error) {
+			return []componego.Component{
+				component1Factory.Build(),
+				newComponent(component2Factory, func(err error) error {
+					require.ErrorIs(t, err, customErr)
+					require.NotErrorIs(t, err, appErr)
+					isHandled = true
+					return nil
+				}),
+			}, nil
+		})
+		appFactory.SetApplicationAction(func(_ componego.Environment, _ any) (int, error) {
+			return componego.ErrorExitCode, appErr
+		})
+		d := driver.New(&driver.Options{
+			AppIO: application.NewIO(nil, &bytes.Buffer{}, &bytes.Buffer{}),
+		})
+		exitCode, err := d.RunApplication(context.Background(), appFactory.Build(), componego.TestMode)
+		require.Equal(t, componego.ErrorExitCode, exitCode)
+		require.ErrorIs(t, err, appErr)
+		require.NotErrorIs(t, err, customErr)
+		require.True(t, isHandled)
+	})
+
+	t.Run("previous error is suppressed", func(t *testing.T) {
+		shutdownErr := errors.New("expected shutdown error")
+		component1Factory := component.NewFactory("component 1", "0.0.1")
+		component1Factory.SetComponentStop(func(_ componego.Environment, prevErr error) error {
+			if errors.Is(prevErr, shutdownErr) {
+				return nil
+			}
+			return prevErr
+		})
+		component2Factory := component.NewFactory("component 2", "0.0.1")
+		component2Factory.SetComponentStop(func(_ componego.Environment, prevErr error) error {
+			return errors.Join(prevErr, shutdownErr)
+		})
+		exitCode, err := runApp(component1Factory.Build(), component2Factory.Build())
+		require.NoError(t, err)
+		require.Equal(t, componego.SuccessExitCode, exitCode)
+	})
+
+	t.Run("dependency error is replaced", func(t *testing.T) {
+		countCalls := 0
+		componentFactory := component.NewFactory("component", "0.0.1")
+		componentFactory.SetComponentDependencies(func() ([]componego.Dependency, error) {
+			countCalls++
+			return []componego.Dependency{
+				func() (*types.AStruct, error) {
+					return nil, customErr
+				},
+			}, nil
+		})
+		isHandled := false
+		exitCode, err := runApp(newComponent(componentFactory, func(err error) error {
+			_, stage, _ := driver.GetErrorComponent(err)
+			require.Equal(t, driver.StageDependencies, stage)
+			require.ErrorIs(t, err, customErr)
+			isHandled = true
+			// The error cannot be ignored.
+			return nil
+		}))
+		require.Equal(t, componego.ErrorExitCode, exitCode)
+		require.ErrorIs(t, err, customErr)
+		require.True(t, isHandled)
+		// The dependencies of the component are not requested again to find the component.
+		require.Equal(t, 1, countCalls)
+	})
+}
+
+func TestComponentConfigDefaults(t *testing.T) {
+	componentFactory := component.NewFactory("component", "0.0.1")
+	componentFactory.SetComponentConfigDefaults(func() (string, map[string]any, error) {
+		return "component", map[string]any{"key": "default value"}, nil
+	})
+	appFactory := application.NewFactory("Component Config Defaults Test Application")
+	appFactory.SetApplicationComponents(func() ([]componego.Component, error) {
+		return []componego.Component{componentFactory.Build()}, nil
+	})
+	d := driver.New(&driver.Options{
+		AppIO: application.NewIO(nil, &bytes.Buffer{}, &bytes.Buffer{}),
+		// The default configuration is added by the driver for any component provider.
+		ComponentProviderFactory: func() (componego.ComponentProvider, func(componego.Environment, any) (func() error, error)) {
+			manager, initializer := component.NewManager()
+			return manager, func(env componego.Environment, _ any) (func() error, error) {
+				components, err := component.ExtractComponents(env.Application())
+				if err != nil {
+					return nil, err
+				}
+				return nil, initializer(components)
+			}
+		},
+	})
+	env, cancelEnv, err := d.CreateEnvironment(context.Background(), appFactory.Build(), componego.TestMode)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, cancelEnv())
+	})
+	value, err := env.ConfigProvider().ConfigValue("component.key", nil)
+	require.NoError(t, err)
+	require.Equal(t, "default value", value)
+}
+
+func TestLifecycleListeners(t *testing.T) {
+	customErr := errors.New("custom error")
+	runApp := func(initErr error, parallel bool) ([]string, error) {
+		mutex := sync.Mutex{}
+		events := make([]string, 0)
+		record := func(prefix string) func(event *driver.LifecycleEvent) {
+			return func(event *driver.LifecycleEvent) {
+				mutex.Lock()
+				defer mutex.Unlock()
+				require.NotNil(t, event.Environment)
+				require.False(t, event.StartedAt.IsZero())
+				name := prefix + " " + string(event.Phase)
+				if event.Component != nil {
+					name += " " + event.Component.ComponentIdentifier()
+				}
+				if event.Err != nil {
+					name += " error"
+				}
+				events = append(events, name)
+			}
+		}
+		componentFactory := component.NewFactory("component", "0.0.1")
+		componentFactory.SetComponentInit(func(_ componego.Environment) error {
+			return initErr
+		})
+		componentFactory.SetComponentStop(func(_ componego.Environment, prevErr error) error {
+			return prevErr
+		})
+		appFactory := application.NewFactory("Lifecycle Test Application")
+		appFactory.SetApplicationComponents(func() ([]componego.Component, error) {
+			return []componego.Component{
+				componentFactory.Build(),
+			}, nil
+		})
+		appFactory.SetApplicationAction(func(_ componego.Environment, _ any) (int, error) {
+			return componego.SuccessExitCode, nil
+		})
+		d := driver.New(&driver.Options{
+			AppIO:                 application.NewIO(nil, &bytes.Buffer{}, &bytes.Buffer{}),
+			ParallelComponentInit: parallel,
+			LifecycleListeners: []driver.LifecycleListener{
+				&driver.LifecycleFuncs{
+					Before: record("before"),
+					After:  record("after"),
+				},
+			},
+		})
+		_, err := d.RunApplication(context.Background(), appFactory.Build(), componego.TestMode)
+		return events, err
+	}
+
+	for _, parallel := range []bool{false, true} {
+		t.Run("successful run", func(t *testing.T) {
+			events, err := runApp(nil, parallel)
+			require.NoError(t, err)
+			require.Equal(t, []string{
+				"before config", "after config",
+				"before components", "after components",
+				"before dependencies", "after dependencies",
+				"before init",
+				"before component:init component", "after component:init component",
+				"after init",
+				"before action", "after action",
+				"before stop",
+				"before component:stop component", "after component:stop component",
+				"after stop",
+			}, events)
+		})
+
+		t.Run("init error", func(t *testing.T) {
+			events, err := runApp(customErr, parallel)
+			require.ErrorIs(t, err, customErr)
+			require.Equal(t, []string{
+				"before config", "after config",
+				"before components", "after components",
+				"before dependencies", "after dependencies",
+				"before init",
+				"before component:init component", "after component:init component error",
+				"after init error",
+				"before stop", "after stop error",
+			}, events)
+		})
+	}
+}
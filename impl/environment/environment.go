@@ -28,6 +28,7 @@ import (
 var (
 	ErrInvalidParentContext   = xerrors.New("new content is not created based on previous context", "E0210")
 	ErrNoEnvironmentInContext = xerrors.New("there is no environment in the context", "E0220")
+	ErrNoComponentProvider    = xerrors.New("environment does not provide access to the component provider", "E0230")
 )
 
 type ContextKey struct{}
@@ -118,6 +119,12 @@ func (e *environment) DependencyInvoker() componego.DependencyInvoker {
 	return e.dependencyInvoker
 }
 
+// ComponentProvider returns an object that provides the list of active components.
+// This method does not belong to interface componego.Environment.
+func (e *environment) ComponentProvider() componego.ComponentProvider {
+	return e.componentProvider
+}
+
 func GetEnvironment(ctx context.Context) (componego.Environment, error) {
 	if env, ok := ctx.Value(ContextKey{}).(componego.Environment); ok {
 		return env, nil
@@ -132,3 +139,14 @@ func GetEnvironmentOrPanic(ctx context.Context) componego.Environment {
 	}
 	return env
 }
+
+// GetComponentProvider returns the component provider that the environment uses.
+// The environment must have the ComponentProvider method, like the default environment implementation.
+func GetComponentProvider(env componego.Environment) (componego.ComponentProvider, error) {
+	if env, ok := env.(interface {
+		ComponentProvider() componego.ComponentProvider
+	}); ok {
+		return env.ComponentProvider(), nil
+	}
+	return nil, ErrNoComponentProvider
+}
@@ -0,0 +1,100 @@
+/*
+Copyright 2024-present Volodymyr Konstanchuk and contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package component
+
+import (
+	"errors"
+
+	"github.com/componego/componego"
+	"github.com/componego/componego/impl/environment/managers/config"
+	"github.com/componego/componego/libs/type-cast"
+)
+
+// Predicate is a function that checks whether the component is active.
+type Predicate = func(env componego.Environment) (bool, error)
+
+// When returns a component that is active only if the predicate returns true.
+// The component manager uses the original component if it is active.
+func When(predicate Predicate, component componego.Component) componego.Component {
+	return &conditionalComponent{
+		predicate: predicate,
+		component: component,
+	}
+}
+
+// InMode returns a predicate that checks whether the application is running in one of the modes.
+func InMode(appModes ...componego.ApplicationMode) Predicate {
+	return func(env componego.Environment) (bool, error) {
+		currentMode := env.ApplicationMode()
+		for _, appMode := range appModes {
+			if appMode == currentMode {
+				return true, nil
+			}
+		}
+		return false, nil
+	}
+}
+
+// ConfigEnabled returns a predicate that checks whether the configuration value is true.
+// The predicate returns false if the value does not exist.
+func ConfigEnabled(configKey string) Predicate {
+	return func(env componego.Environment) (bool, error) {
+		// Dependencies are not initialized yet, so we cannot use processors that require dependencies.
+		value, err := env.ConfigProvider().ConfigValue(configKey, nil)
+		if errors.Is(err, config.ErrValueNotFound) {
+			return false, nil
+		} else if err != nil {
+			return false, err
+		}
+		return type_cast.ToBool(value)
+	}
+}
+
+type conditionalComponent struct {
+	predicate Predicate
+	component componego.Component
+}
+
+// ComponentIdentifier belongs to interface componego.Component.
+func (c *conditionalComponent) ComponentIdentifier() string {
+	return c.component.ComponentIdentifier()
+}
+
+// ComponentVersion belongs to interface componego.Component.
+func (c *conditionalComponent) ComponentVersion() string {
+	return c.component.ComponentVersion()
+}
+
+// ComponentCondition belongs to interface componego.ComponentCondition.
+func (c *conditionalComponent) ComponentCondition(env componego.Environment) (bool, error) {
+	if ok, err := c.predicate(env); !ok || err != nil {
+		return false, err
+	}
+	if component, ok := c.component.(componego.ComponentCondition); ok {
+		return component.ComponentCondition(env)
+	}
+	return true, nil
+}
+
+func (c *conditionalComponent) unwrap() componego.Component {
+	return c.component
+}
+
+var (
+	_ componego.Component          = (*conditionalComponent)(nil)
+	_ componego.ComponentCondition = (*conditionalComponent)(nil)
+)
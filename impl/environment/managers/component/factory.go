@@ -17,26 +17,55 @@ limitations under the License.
 package component
 
 import (
+	"context"
+	"time"
+
 	"github.com/componego/componego"
 )
 
 type Factory interface {
 	SetComponentIdentifier(identifier string)
 	SetComponentVersion(version string)
+	SetComponentMetadata(metadata componego.Metadata)
+	SetComponentCondition(condition func(env componego.Environment) (bool, error))
 	SetComponentComponents(components func() ([]componego.Component, error))
+	SetComponentAfter(after func() ([]string, error))
+	SetComponentVersionConstraints(versionConstraints func() (map[string]string, error))
+	SetComponentConfigDefaults(configDefaults func() (string, map[string]any, error))
+	SetComponentConfigStatic(configStatic func() []string)
+	SetComponentConfigSchema(configSchema func() (string, []*componego.ConfigKey, error))
 	SetComponentDependencies(dependencies func() ([]componego.Dependency, error))
 	SetComponentInit(init func(env componego.Environment) error)
 	SetComponentStop(stop func(env componego.Environment, prevErr error) error)
+	SetComponentErrorHandler(errorHandler func(err error, appIO componego.ApplicationIO, appMode componego.ApplicationMode) error)
+	SetComponentTimeout(timeout func() (time.Duration, time.Duration))
+	SetComponentHealth(health func(ctx context.Context) error)
+	SetComponentLiveness(liveness func(ctx context.Context) error)
+	SetComponentRun(run func(ctx context.Context) error)
+	SetComponentRestart(restart func() (componego.RestartPolicy, time.Duration))
 	Build() componego.Component
 }
 
 type factory struct {
-	identifier   string
-	version      string
-	components   func() ([]componego.Component, error)
-	dependencies func() ([]componego.Dependency, error)
-	init         func(env componego.Environment) error
-	stop         func(env componego.Environment, prevErr error) error
+	identifier         string
+	version            string
+	metadata           componego.Metadata
+	condition          func(env componego.Environment) (bool, error)
+	components         func() ([]componego.Component, error)
+	after              func() ([]string, error)
+	versionConstraints func() (map[string]string, error)
+	configDefaults     func() (string, map[string]any, error)
+	configStatic       func() []string
+	configSchema       func() (string, []*componego.ConfigKey, error)
+	dependencies       func() ([]componego.Dependency, error)
+	init               func(env componego.Environment) error
+	stop               func(env componego.Environment, prevErr error) error
+	errorHandler       func(err error, appIO componego.ApplicationIO, appMode componego.ApplicationMode) error
+	timeout            func() (time.Duration, time.Duration)
+	health             func(ctx context.Context) error
+	liveness           func(ctx context.Context) error
+	run                func(ctx context.Context) error
+	restart            func() (componego.RestartPolicy, time.Duration)
 }
 
 func NewFactory(identifier string, version string) Factory {
@@ -56,11 +85,46 @@ func (f *factory) SetComponentVersion(version string) {
 	f.version = version
 }
 
+// SetComponentMetadata belongs to interface Factory.
+func (f *factory) SetComponentMetadata(metadata componego.Metadata) {
+	f.metadata = metadata
+}
+
+// SetComponentCondition belongs to interface Factory.
+func (f *factory) SetComponentCondition(condition func(env componego.Environment) (bool, error)) {
+	f.condition = condition
+}
+
 // SetComponentComponents belongs to interface Factory.
 func (f *factory) SetComponentComponents(components func() ([]componego.Component, error)) {
 	f.components = components
 }
 
+// SetComponentAfter belongs to interface Factory.
+func (f *factory) SetComponentAfter(after func() ([]string, error)) {
+	f.after = after
+}
+
+// SetComponentVersionConstraints belongs to interface Factory.
+func (f *factory) SetComponentVersionConstraints(versionConstraints func() (map[string]string, error)) {
+	f.versionConstraints = versionConstraints
+}
+
+// SetComponentConfigDefaults belongs to interface Factory.
+func (f *factory) SetComponentConfigDefaults(configDefaults func() (string, map[string]any, error)) {
+	f.configDefaults = configDefaults
+}
+
+// SetComponentConfigStatic belongs to interface Factory.
+func (f *factory) SetComponentConfigStatic(configStatic func() []string) {
+	f.configStatic = configStatic
+}
+
+// SetComponentConfigSchema belongs to interface Factory.
+func (f *factory) SetComponentConfigSchema(configSchema func() (string, []*componego.ConfigKey, error)) {
+	f.configSchema = configSchema
+}
+
 // SetComponentDependencies belongs to interface Factory.
 func (f *factory) SetComponentDependencies(dependencies func() ([]componego.Dependency, error)) {
 	f.dependencies = dependencies
@@ -76,25 +140,135 @@ func (f *factory) SetComponentStop(stop func(env componego.Environment, prevErr
 	f.stop = stop
 }
 
+// SetComponentErrorHandler belongs to interface Factory.
+func (f *factory) SetComponentErrorHandler(errorHandler func(err error, appIO componego.ApplicationIO, appMode componego.ApplicationMode) error) {
+	f.errorHandler = errorHandler
+}
+
+// SetComponentTimeout belongs to interface Factory.
+func (f *factory) SetComponentTimeout(timeout func() (time.Duration, time.Duration)) {
+	f.timeout = timeout
+}
+
+// SetComponentHealth belongs to interface Factory.
+func (f *factory) SetComponentHealth(health func(ctx context.Context) error) {
+	f.health = health
+}
+
+// SetComponentLiveness belongs to interface Factory.
+func (f *factory) SetComponentLiveness(liveness func(ctx context.Context) error) {
+	f.liveness = liveness
+}
+
+// SetComponentRun belongs to interface Factory.
+func (f *factory) SetComponentRun(run func(ctx context.Context) error) {
+	f.run = run
+}
+
+// SetComponentRestart belongs to interface Factory.
+func (f *factory) SetComponentRestart(restart func() (componego.RestartPolicy, time.Duration)) {
+	f.restart = restart
+}
+
 // Build belongs to interface Factory.
 func (f *factory) Build() componego.Component {
 	return &QuickComponent{
-		Identifier:   f.identifier,
-		Version:      f.version,
-		Components:   f.components,
-		Dependencies: f.dependencies,
-		Init:         f.init,
-		Stop:         f.stop,
+		Identifier:         f.identifier,
+		Version:            f.version,
+		Metadata:           f.metadata,
+		Condition:          f.condition,
+		Components:         f.components,
+		After:              f.after,
+		VersionConstraints: f.versionConstraints,
+		ConfigDefaults:     f.configDefaults,
+		ConfigStatic:       f.configStatic,
+		ConfigSchema:       f.configSchema,
+		Dependencies:       f.dependencies,
+		Init:               f.init,
+		Stop:               f.stop,
+		ErrorHandler:       f.errorHandler,
+		Timeout:            f.timeout,
+		Health:             f.health,
+		Liveness:           f.liveness,
+		Run:                f.run,
+		Restart:            f.restart,
 	}
 }
 
 type QuickComponent struct {
-	Identifier   string
-	Version      string
-	Components   func() ([]componego.Component, error)
-	Dependencies func() ([]componego.Dependency, error)
-	Init         func(env componego.Environment) error
-	Stop         func(env componego.Environment, prevErr error) error
+	Identifier         string
+	Version            string
+	Metadata           componego.Metadata
+	Condition          func(env componego.Environment) (bool, error)
+	Components         func() ([]componego.Component, error)
+	After              func() ([]string, error)
+	VersionConstraints func() (map[string]string, error)
+	ConfigDefaults     func() (string, map[string]any, error)
+	ConfigStatic       func() []string
+	ConfigSchema       func() (string, []*componego.ConfigKey, error)
+	Dependencies       func() ([]componego.Dependency, error)
+	Init               func(env componego.Environment) error
+	Stop               func(env componego.Environment, prevErr error) error
+	ErrorHandler       func(err error, appIO componego.ApplicationIO, appMode componego.ApplicationMode) error
+	Timeout            func() (time.Duration, time.Duration)
+	Health             func(ctx context.Context) error
+	Liveness           func(ctx context.Context) error
+	Run                func(ctx context.Context) error
+	Restart            func() (componego.RestartPolicy, time.Duration)
+}
+
+// Implements returns the component as the interface T if the component implements this interface.
+// Components created by the factory implement all interfaces,
+// so the second value is false if the function of the interface was not set in the factory.
+func Implements[T componego.Component](component componego.Component) (T, bool) {
+	result, ok := component.(T)
+	if !ok {
+		return result, false
+	}
+	if quickComponent, ok := component.(*QuickComponent); ok {
+		return result, quickComponent.implements(any((*T)(nil)))
+	}
+	return result, true
+}
+
+// implements returns false if the function of the interface is not set.
+// The argument is a nil pointer to the interface.
+func (q *QuickComponent) implements(reflectInterface any) bool {
+	switch reflectInterface.(type) {
+	case *componego.ComponentCondition:
+		return q.Condition != nil
+	case *componego.ComponentComponents:
+		return q.Components != nil
+	case *componego.ComponentAfter:
+		return q.After != nil
+	case *componego.ComponentVersionConstraints:
+		return q.VersionConstraints != nil
+	case *componego.ComponentConfigDefaults:
+		return q.ConfigDefaults != nil
+	case *componego.ComponentConfigStatic:
+		return q.ConfigStatic != nil
+	case *componego.ComponentConfigSchema:
+		return q.ConfigSchema != nil
+	case *componego.ComponentDependencies:
+		return q.Dependencies != nil
+	case *componego.ComponentInit:
+		return q.Init != nil
+	case *componego.ComponentStop:
+		return q.Stop != nil
+	case *componego.ComponentErrorHandler:
+		return q.ErrorHandler != nil
+	case *componego.ComponentTimeout:
+		return q.Timeout != nil
+	case *componego.ComponentHealth:
+		return q.Health != nil
+	case *componego.ComponentLiveness:
+		return q.Liveness != nil
+	case *componego.ComponentRun:
+		return q.Run != nil
+	case *componego.ComponentRestart:
+		return q.Restart != nil
+	}
+	return true
 }
 
 // ComponentIdentifier belongs to interface componego.Component.
@@ -107,6 +281,19 @@ func (q *QuickComponent) ComponentVersion() string {
 	return q.Version
 }
 
+// ComponentMetadata belongs to interface componego.ComponentMetadata.
+func (q *QuickComponent) ComponentMetadata() componego.Metadata {
+	return q.Metadata
+}
+
+// ComponentCondition belongs to interface componego.ComponentCondition.
+func (q *QuickComponent) ComponentCondition(env componego.Environment) (bool, error) {
+	if q.Condition == nil {
+		return true, nil
+	}
+	return q.Condition(env)
+}
+
 // ComponentComponents belongs to interface componego.ComponentComponents.
 func (q *QuickComponent) ComponentComponents() ([]componego.Component, error) {
 	if q.Components == nil {
@@ -115,6 +302,46 @@ func (q *QuickComponent) ComponentComponents() ([]componego.Component, error) {
 	return q.Components()
 }
 
+// ComponentAfter belongs to interface componego.ComponentAfter.
+func (q *QuickComponent) ComponentAfter() ([]string, error) {
+	if q.After == nil {
+		return nil, nil
+	}
+	return q.After()
+}
+
+// ComponentVersionConstraints belongs to interface componego.ComponentVersionConstraints.
+func (q *QuickComponent) ComponentVersionConstraints() (map[string]string, error) {
+	if q.VersionConstraints == nil {
+		return nil, nil
+	}
+	return q.VersionConstraints()
+}
+
+// ComponentConfigDefaults belongs to interface componego.ComponentConfigDefaults.
+func (q *QuickComponent) ComponentConfigDefaults() (string, map[string]any, error) {
+	if q.ConfigDefaults == nil {
+		return "", nil, nil
+	}
+	return q.ConfigDefaults()
+}
+
+// ComponentConfigStatic belongs to interface componego.ComponentConfigStatic.
+func (q *QuickComponent) ComponentConfigStatic() []string {
+	if q.ConfigStatic == nil {
+		return nil
+	}
+	return q.ConfigStatic()
+}
+
+// ComponentConfigSchema belongs to interface componego.ComponentConfigSchema.
+func (q *QuickComponent) ComponentConfigSchema() (string, []*componego.ConfigKey, error) {
+	if q.ConfigSchema == nil {
+		return "", nil, nil
+	}
+	return q.ConfigSchema()
+}
+
 // ComponentDependencies belongs to interface componego.ComponentDependencies.
 func (q *QuickComponent) ComponentDependencies() ([]componego.Dependency, error) {
 	if q.Dependencies == nil {
@@ -139,10 +366,74 @@ func (q *QuickComponent) ComponentStop(env componego.Environment, prevErr error)
 	return q.Stop(env, prevErr)
 }
 
+// ComponentErrorHandler belongs to interface componego.ComponentErrorHandler.
+func (q *QuickComponent) ComponentErrorHandler(err error, appIO componego.ApplicationIO, appMode componego.ApplicationMode) error {
+	if q.ErrorHandler == nil {
+		return err
+	}
+	return q.ErrorHandler(err, appIO, appMode)
+}
+
+// ComponentTimeout belongs to interface componego.ComponentTimeout.
+func (q *QuickComponent) ComponentTimeout() (time.Duration, time.Duration) {
+	if q.Timeout == nil {
+		return 0, 0
+	}
+	return q.Timeout()
+}
+
+// ComponentHealthCheck belongs to interface componego.ComponentHealth.
+// The health registry ignores this check if it is not set.
+func (q *QuickComponent) ComponentHealthCheck(ctx context.Context) error {
+	if q.Health == nil {
+		return nil
+	}
+	return q.Health(ctx)
+}
+
+// ComponentLivenessCheck belongs to interface componego.ComponentLiveness.
+// The health registry ignores this check if it is not set.
+func (q *QuickComponent) ComponentLivenessCheck(ctx context.Context) error {
+	if q.Liveness == nil {
+		return nil
+	}
+	return q.Liveness(ctx)
+}
+
+// ComponentRun belongs to interface componego.ComponentRun.
+// The background service is not started if it is not set.
+func (q *QuickComponent) ComponentRun(ctx context.Context) error {
+	if q.Run == nil {
+		return nil
+	}
+	return q.Run(ctx)
+}
+
+// ComponentRestart belongs to interface componego.ComponentRestart.
+func (q *QuickComponent) ComponentRestart() (componego.RestartPolicy, time.Duration) {
+	if q.Restart == nil {
+		return componego.RestartNever, 0
+	}
+	return q.Restart()
+}
+
 var (
-	_ componego.Component             = (*QuickComponent)(nil)
-	_ componego.ComponentComponents   = (*QuickComponent)(nil)
-	_ componego.ComponentDependencies = (*QuickComponent)(nil)
-	_ componego.ComponentInit         = (*QuickComponent)(nil)
-	_ componego.ComponentStop         = (*QuickComponent)(nil)
+	_ componego.Component                   = (*QuickComponent)(nil)
+	_ componego.ComponentMetadata           = (*QuickComponent)(nil)
+	_ componego.ComponentCondition          = (*QuickComponent)(nil)
+	_ componego.ComponentComponents         = (*QuickComponent)(nil)
+	_ componego.ComponentAfter              = (*QuickComponent)(nil)
+	_ componego.ComponentVersionConstraints = (*QuickComponent)(nil)
+	_ componego.ComponentConfigDefaults     = (*QuickComponent)(nil)
+	_ componego.ComponentConfigStatic       = (*QuickComponent)(nil)
+	_ componego.ComponentConfigSchema       = (*QuickComponent)(nil)
+	_ componego.ComponentDependencies       = (*QuickComponent)(nil)
+	_ componego.ComponentInit               = (*QuickComponent)(nil)
+	_ componego.ComponentStop               = (*QuickComponent)(nil)
+	_ componego.ComponentErrorHandler       = (*QuickComponent)(nil)
+	_ componego.ComponentTimeout            = (*QuickComponent)(nil)
+	_ componego.ComponentHealth             = (*QuickComponent)(nil)
+	_ componego.ComponentLiveness           = (*QuickComponent)(nil)
+	_ componego.ComponentRun                = (*QuickComponent)(nil)
+	_ componego.ComponentRestart            = (*QuickComponent)(nil)
 )
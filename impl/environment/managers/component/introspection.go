@@ -0,0 +1,188 @@
+/*
+Copyright 2024-present Volodymyr Konstanchuk and contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package component
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/componego/componego"
+	"github.com/componego/componego/impl/environment"
+	"github.com/componego/componego/impl/environment/managers/config"
+	"github.com/componego/componego/impl/environment/managers/dependency"
+	"github.com/componego/componego/libs/xerrors"
+)
+
+// Description describes all active components of the application.
+type Description struct {
+	// Roots are identifiers of active components that were returned by the application.
+	Roots []string `json:"roots"`
+	// Nodes are sorted in the order of component initialization.
+	Nodes []*Node `json:"components"`
+}
+
+// Node describes an active component.
+type Node struct {
+	Identifier string              `json:"identifier"`
+	Version    string              `json:"version"`
+	Metadata   componego.Metadata  `json:"metadata"`
+	Component  componego.Component `json:"-"`
+	// Parents are identifiers of active components that returned this component as a child component.
+	Parents []string `json:"parents,omitempty"`
+	// Children are identifiers of active child components.
+	Children []string `json:"children,omitempty"`
+	// After are identifiers of active components that this component follows.
+	After []string `json:"after,omitempty"`
+	// Dependencies are the types provided by the dependencies of the component.
+	Dependencies []string `json:"dependencies,omitempty"`
+	// ConfigKeys are the keys of the default configuration and the configuration schema of the component.
+	ConfigKeys []string `json:"configKeys,omitempty"`
+}
+
+// Describe returns the description of all active components of the application.
+// The description is created every time, so you should not call this function often.
+func Describe(env componego.Environment) (*Description, error) {
+	componentProvider, err := environment.GetComponentProvider(env)
+	if err != nil {
+		return nil, err
+	}
+	treeProvider, ok := componentProvider.(TreeProvider)
+	if !ok {
+		return nil, ErrDescribe.WithMessage("component provider does not describe the component tree", "E0427",
+			xerrors.NewOption("componego:component:provider", componentProvider),
+		)
+	}
+	// Dependencies are described only if the dependency invoker remembers them.
+	typeProvider, _ := env.DependencyInvoker().(dependency.TypeProvider)
+	components := env.Components()
+	description := &Description{
+		Roots: treeProvider.ComponentRoots(),
+		Nodes: make([]*Node, len(components)),
+	}
+	nodeMap := make(map[string]*Node, len(components))
+	for i, component := range components {
+		node := &Node{
+			Identifier: component.ComponentIdentifier(),
+			Version:    component.ComponentVersion(),
+			Component:  component,
+			Children:   treeProvider.ComponentChildren(component.ComponentIdentifier()),
+			After:      treeProvider.ComponentAfter(component.ComponentIdentifier()),
+		}
+		if component, ok := component.(componego.ComponentMetadata); ok {
+			node.Metadata = component.ComponentMetadata()
+		}
+		if typeProvider != nil {
+			for _, providedType := range typeProvider.ComponentTypes(node.Identifier) {
+				node.Dependencies = append(node.Dependencies, providedType.String())
+			}
+		}
+		if node.ConfigKeys, err = getComponentConfigKeys(component); err != nil {
+			return nil, err
+		}
+		description.Nodes[i] = node
+		nodeMap[node.Identifier] = node
+	}
+	for _, node := range description.Nodes {
+		for _, identifier := range node.Children {
+			if child, ok := nodeMap[identifier]; ok {
+				child.Parents = append(child.Parents, node.Identifier)
+			}
+		}
+	}
+	return description, nil
+}
+
+// getComponentConfigKeys returns the sorted keys of the default configuration and the configuration schema of the component.
+func getComponentConfigKeys(component componego.Component) ([]string, error) {
+	result := make([]string, 0)
+	if component, ok := component.(componego.ComponentConfigDefaults); ok {
+		namespace, defaults, err := component.ComponentConfigDefaults()
+		if err != nil {
+			return nil, ErrDescribe.WithError(err, "E0429",
+				xerrors.NewOption("componego:component:component", component),
+			)
+		}
+		result = append(result, getConfigKeys(namespace, defaults)...)
+	}
+	schema, err := config.GetSchema([]componego.Component{component})
+	if err != nil {
+		return nil, ErrDescribe.WithError(err, "E0428",
+			xerrors.NewOption("componego:component:component", component),
+		)
+	}
+	for _, schemaKey := range schema {
+		result = append(result, schemaKey.Key)
+	}
+	if len(result) == 0 {
+		return nil, nil
+	}
+	sort.Strings(result)
+	// The schema usually describes keys that also have default values.
+	uniqueKeys := result[:1]
+	for _, key := range result[1:] {
+		if key != uniqueKeys[len(uniqueKeys)-1] {
+			uniqueKeys = append(uniqueKeys, key)
+		}
+	}
+	return uniqueKeys, nil
+}
+
+func getConfigKeys(prefix string, defaults map[string]any) []string {
+	result := make([]string, 0, len(defaults))
+	for key, value := range defaults {
+		key = config.JoinKey(prefix, key)
+		if nestedConfig, ok := value.(map[string]any); ok && len(nestedConfig) > 0 {
+			result = append(result, getConfigKeys(key, nestedConfig)...)
+			continue
+		}
+		result = append(result, key)
+	}
+	return result
+}
+
+// String returns the component tree as text. Each component is shown only once.
+func (d *Description) String() string {
+	nodeMap := make(map[string]*Node, len(d.Nodes))
+	for _, node := range d.Nodes {
+		nodeMap[node.Identifier] = node
+	}
+	builder := &strings.Builder{}
+	visited := make(map[string]bool, len(d.Nodes))
+	var render func(identifiers []string, depth int)
+	render = func(identifiers []string, depth int) {
+		for _, identifier := range identifiers {
+			node, ok := nodeMap[identifier]
+			if !ok {
+				continue
+			}
+			builder.WriteString(strings.Repeat("  ", depth))
+			builder.WriteString(node.Identifier + "@" + node.Version)
+			if node.Metadata.Description != "" {
+				builder.WriteString(" - " + node.Metadata.Description)
+			}
+			if visited[identifier] {
+				builder.WriteString(" (see above)\n")
+				continue
+			}
+			builder.WriteString("\n")
+			visited[identifier] = true
+			render(node.Children, depth+1)
+		}
+	}
+	render(d.Roots, 0)
+	return builder.String()
+}
@@ -17,24 +17,138 @@ limitations under the License.
 package component
 
 import (
+	"errors"
+	"reflect"
 	"sort"
+	"strings"
 
 	"github.com/componego/componego"
+	"github.com/componego/componego/impl/environment"
+	"github.com/componego/componego/impl/environment/managers/config"
+	"github.com/componego/componego/internal/developer"
 	"github.com/componego/componego/internal/utils"
+	"github.com/componego/componego/libs/semver"
 	"github.com/componego/componego/libs/xerrors"
 )
 
 var (
 	ErrComponentManager   = xerrors.New("error inside component manager", "E0410")
 	ErrCyclicDependencies = ErrComponentManager.WithMessage("cycle detected in dependencies between components", "E0411")
+	ErrVersionConstraint  = ErrComponentManager.WithMessage("invalid component version constraint", "E0414")
+	ErrVersionConflict    = ErrComponentManager.WithMessage("component version constraints are not satisfied", "E0415")
+	ErrIdentifierConflict = ErrComponentManager.WithMessage("several components have the same identifier", "E0419")
+	ErrComponentCondition = ErrComponentManager.WithMessage("error while checking the component condition", "E0422")
+	ErrDescribe           = ErrComponentManager.WithMessage("error while describing components", "E0426")
+	ErrDisabledComponent  = ErrComponentManager.WithMessage("disabled component is required by an enabled component", "E0430")
 )
 
+// DisabledComponentsConfigKey is the configuration key that contains identifiers of disabled components.
+// The value can be a list of strings or a string with identifiers separated by commas.
+const DisabledComponentsConfigKey = "componego.components.disabled"
+
+// ConflictPolicy describes what the manager does when several components have the same identifier.
+type ConflictPolicy int
+
+const (
+	// AllowConflicts allows components to be overridden silently. This is the default policy.
+	AllowConflicts ConflictPolicy = iota
+	// WarnConflicts allows components to be overridden, but shows a warning for each override in developer mode.
+	WarnConflicts
+	// ForbidConflicts returns an error if any component is overridden.
+	ForbidConflicts
+)
+
+// OverrideProvider is an interface that describes a component provider that reports overridden components.
+type OverrideProvider interface {
+	componego.ComponentProvider
+	// ComponentOverrides returns a list of components that were replaced by other components with the same identifier.
+	ComponentOverrides() []*Override
+}
+
+// OrderProvider is an interface that describes a component provider that knows the order between components.
+type OrderProvider interface {
+	componego.ComponentProvider
+	// ComponentPrevious returns identifiers of active components that must be initialized before the component.
+	// This includes child components and components that the component should follow.
+	ComponentPrevious(identifier string) []string
+}
+
+// TreeProvider is an interface that describes a component provider that knows the edges between components.
+type TreeProvider interface {
+	OrderProvider
+	// ComponentRoots returns identifiers of active components that were returned by the application.
+	ComponentRoots() []string
+	// ComponentChildren returns identifiers of active child components of the component.
+	ComponentChildren(identifier string) []string
+	// ComponentAfter returns identifiers of active components that the component follows.
+	ComponentAfter(identifier string) []string
+}
+
+// Override describes a component that was replaced by another component with the same identifier.
+// Components with the same identifier, version and type are considered the same component and are not reported.
+type Override struct {
+	// Identifier is the identifier of both components.
+	Identifier string
+	// Component is the component that is used in the application.
+	Component componego.Component
+	// ComponentParents is a list of components from the root that lead to the used component.
+	ComponentParents []componego.Component
+	// Replaced is the component that was replaced.
+	Replaced componego.Component
+	// ReplacedParents is a list of components from the root that lead to the replaced component.
+	ReplacedParents []componego.Component
+}
+
+// String returns the text representation of the override.
+func (o *Override) String() string {
+	return getComponentPath(o.ReplacedParents, o.Replaced) + " was replaced by " + getComponentPath(o.ComponentParents, o.Component)
+}
+
+// VersionConflict describes a component whose version does not satisfy the constraint of another component.
+type VersionConflict struct {
+	// Component is the component that declared the constraint.
+	Component componego.Component
+	// Dependency is the active component that does not satisfy the constraint.
+	Dependency componego.Component
+	// Constraint is the constraint declared by the component.
+	Constraint string
+	// FoundVersion is the version of the active component.
+	FoundVersion string
+}
+
+// String returns the text representation of the conflict.
+func (v *VersionConflict) String() string {
+	return v.Component.ComponentIdentifier() + "@" + v.Component.ComponentVersion() + " requires " +
+		v.Dependency.ComponentIdentifier() + " " + v.Constraint + ", but found " + v.FoundVersion
+}
+
 type manager struct {
+	policy     ConflictPolicy
 	components []componego.Component
+	overrides  []*Override
+	roots      []string
+	children   map[string][]string
+	after      map[string][]string
 }
 
 func NewManager() (componego.ComponentProvider, func(components []componego.Component) error) {
-	m := &manager{}
+	m := &manager{
+		policy: AllowConflicts,
+	}
+	return m, func(components []componego.Component) error {
+		// The environment is not available, so conditions of components receive nil,
+		// and components cannot be disabled by the configuration.
+		return m.initialize(nil, components)
+	}
+}
+
+// NewManagerWithPolicy is similar to NewManager, but uses the given policy for components with the same identifier.
+// Its initializer receives the environment, which is used by conditions of components and by the configuration
+// of disabled components.
+func NewManagerWithPolicy(policy ConflictPolicy) (componego.ComponentProvider, func(env componego.Environment, components []componego.Component) error) {
+	m := &manager{
+		policy: policy,
+	}
 	return m, m.initialize
 }
 
@@ -46,21 +160,79 @@ func (m *manager) Components() []componego.Component {
 	return utils.Copy(m.components)
 }
 
-func (m *manager) initialize(components []componego.Component) error {
+// ComponentOverrides belongs to interface OverrideProvider.
+func (m *manager) ComponentOverrides() []*Override {
+	if len(m.overrides) == 0 {
+		return nil
+	}
+	return utils.Copy(m.overrides)
+}
+
+// ComponentPrevious belongs to interface OrderProvider.
+func (m *manager) ComponentPrevious(identifier string) []string {
+	if len(m.children[identifier]) == 0 && len(m.after[identifier]) == 0 {
+		return nil
+	}
+	return append(m.ComponentChildren(identifier), m.after[identifier]...)
+}
+
+// ComponentRoots belongs to interface TreeProvider.
+func (m *manager) ComponentRoots() []string {
+	if len(m.roots) == 0 {
+		return nil
+	}
+	return utils.Copy(m.roots)
+}
+
+// ComponentChildren belongs to interface TreeProvider.
+func (m *manager) ComponentChildren(identifier string) []string {
+	if len(m.children[identifier]) == 0 {
+		return nil
+	}
+	return utils.Copy(m.children[identifier])
+}
+
+// ComponentAfter belongs to interface TreeProvider.
+func (m *manager) ComponentAfter(identifier string) []string {
+	if len(m.after[identifier]) == 0 {
+		return nil
+	}
+	return utils.Copy(m.after[identifier])
+}
+
+func (m *manager) initialize(env componego.Environment, components []componego.Component) error {
 	if len(components) == 0 {
 		return nil
 	}
+	disabled, err := getDisabledIdentifiers(env)
+	if err != nil {
+		return err
+	}
+	overrides := make([]*Override, 0)
 	componentStack := make([]*stackItem, 0, len(components)*2)
 	componentMap := make(map[string]*stackItem, len(components)*2)
-	for _, component := range components {
+	rootIdentifiers := make([]string, len(components))
+	for i, component := range components {
+		rootIdentifiers[i] = component.ComponentIdentifier()
 		componentStack = append(componentStack, &stackItem{
-			identifier: component.ComponentIdentifier(),
+			identifier: rootIdentifiers[i],
 			component:  component,
 		})
 	}
 	for position := 0; len(componentStack) > 0; position++ {
 		item := componentStack[len(componentStack)-1]
 		componentStack = componentStack[:len(componentStack)-1]
+		// Disabled components are removed together with their child components that are not used by other components.
+		if disabled[item.identifier] {
+			continue
+		}
+		// Inactive components are ignored together with their child components.
+		// They also cannot replace other components.
+		if isActive, err := checkCondition(env, item); err != nil {
+			return err
+		} else if !isActive {
+			continue
+		}
 		// You can rewrite the components using component ID.
 		if oldItem, ok := componentMap[item.identifier]; ok {
 			parent := item.parent
@@ -74,6 +246,15 @@ func (m *manager) initialize(components []componego.Component) error {
 					xerrors.NewCallableOption("component:cyclicDependencies", cyclicDependencyProvider),
 				)
 			}
+			if isOverride(oldItem.component, item.component) {
+				overrides = append(overrides, &Override{
+					Identifier:       item.identifier,
+					Component:        oldItem.component,
+					ComponentParents: getParentComponents(oldItem),
+					Replaced:         item.component,
+					ReplacedParents:  getParentComponents(item),
+				})
+			}
 			// This prevents visiting components that make no sense to visit.
 			// Because this component has already been fully received.
 			// Because we are checking them from the end.
@@ -90,6 +271,12 @@ func (m *manager) initialize(components []componego.Component) error {
 			for _, component := range components {
 				// It is important for each new object to call this function only once during component initialization.
 				identifier := component.ComponentIdentifier()
+				if disabled[identifier] {
+					return ErrDisabledComponent.WithOptions("E0433",
+						xerrors.NewOption("componego:component:component", item.component),
+						xerrors.NewOption("componego:component:disabled", identifier),
+					)
+				}
 				componentStack = append(componentStack, &stackItem{
 					identifier: identifier,
 					component:  component,
@@ -104,7 +291,61 @@ func (m *manager) initialize(components []componego.Component) error {
 	for _, item := range componentMap {
 		componentStack = append(componentStack, item)
 	}
-	m.components = getSortedComponents(componentStack, componentMap)
+	sortedItems, err := sortBySoftOrder(getSortedComponents(componentStack, componentMap), componentMap)
+	if err != nil {
+		return err
+	}
+	components = make([]componego.Component, len(sortedItems))
+	children := make(map[string][]string, len(sortedItems))
+	after := make(map[string][]string, len(sortedItems))
+	for i, item := range sortedItems {
+		components[i] = item.component
+		for _, identifier := range item.children {
+			if componentMap[identifier] != nil {
+				children[item.identifier] = append(children[item.identifier], identifier)
+			}
+		}
+		after[item.identifier] = item.after
+	}
+	roots := make([]string, 0, len(rootIdentifiers))
+	for _, identifier := range rootIdentifiers {
+		if componentMap[identifier] != nil && !utils.Contains(roots, identifier) {
+			roots = append(roots, identifier)
+		}
+	}
+	// Versions are checked only for the components that remained after all rewrites.
+	if err := checkVersionConstraints(components, componentMap); err != nil {
+		return err
+	}
+	if err := m.checkOverrides(env, overrides); err != nil {
+		return err
+	}
+	m.components = components
+	m.overrides = overrides
+	m.roots = roots
+	m.children = children
+	m.after = after
+	return nil
+}
+
+func (m *manager) checkOverrides(env componego.Environment, overrides []*Override) error {
+	if len(overrides) == 0 {
+		return nil
+	}
+	switch m.policy {
+	case ForbidConflicts:
+		return ErrIdentifierConflict.WithOptions("E0420",
+			xerrors.NewOption("componego:component:overrides", overrides),
+		)
+	case WarnConflicts:
+		if env == nil || env.ApplicationMode() != componego.DeveloperMode {
+			return nil
+		}
+		writer := env.ApplicationIO().OutputWriter()
+		for _, override := range overrides {
+			developer.Warning(writer, "Component", override.String())
+		}
+	}
 	return nil
 }
 
@@ -121,6 +362,159 @@ func ExtractComponents(app componego.Application) ([]componego.Component, error)
 	return nil, nil
 }
 
+func checkVersionConstraints(components []componego.Component, componentMap map[string]*stackItem) error {
+	var conflicts []*VersionConflict
+	for _, component := range components {
+		component, ok := component.(componego.ComponentVersionConstraints)
+		if !ok {
+			continue
+		}
+		constraints, err := component.ComponentVersionConstraints()
+		if err != nil {
+			return ErrVersionConstraint.WithError(err, "E0416",
+				xerrors.NewOption("componego:component:component", component),
+			)
+		}
+		// The map does not guarantee the order, but the list of conflicts should always be the same.
+		identifiers := utils.Keys(constraints)
+		sort.Strings(identifiers)
+		for _, identifier := range identifiers {
+			item, ok := componentMap[identifier]
+			if !ok {
+				continue
+			}
+			constraint, err := semver.ParseConstraint(constraints[identifier])
+			if err != nil {
+				return ErrVersionConstraint.WithError(err, "E0417",
+					xerrors.NewOption("componego:component:component", component),
+					xerrors.NewOption("componego:component:constraint", constraints[identifier]),
+				)
+			}
+			foundVersion := item.component.ComponentVersion()
+			if version, err := semver.Parse(foundVersion); err == nil && constraint.Check(version) {
+				continue
+			}
+			conflicts = append(conflicts, &VersionConflict{
+				Component:    component,
+				Dependency:   item.component,
+				Constraint:   constraint.String(),
+				FoundVersion: foundVersion,
+			})
+		}
+	}
+	if len(conflicts) == 0 {
+		return nil
+	}
+	return ErrVersionConflict.WithOptions("E0418",
+		xerrors.NewOption("componego:component:versionConflicts", conflicts),
+	)
+}
+
+// GetOverrides returns a list of components that were replaced by other components with the same identifier.
+func GetOverrides(env componego.Environment) ([]*Override, error) {
+	componentProvider, err := environment.GetComponentProvider(env)
+	if err != nil {
+		return nil, err
+	}
+	if componentProvider, ok := componentProvider.(OverrideProvider); ok {
+		return componentProvider.ComponentOverrides(), nil
+	}
+	return nil, ErrComponentManager.WithMessage("component provider does not report overridden components", "E0421",
+		xerrors.NewOption("componego:component:provider", componentProvider),
+	)
+}
+
+func getDisabledIdentifiers(env componego.Environment) (map[string]bool, error) {
+	if env == nil || env.ConfigProvider() == nil {
+		return nil, nil
+	}
+	// Dependencies are not initialized yet, so we cannot use processors that require dependencies.
+	value, err := env.ConfigProvider().ConfigValue(DisabledComponentsConfigKey, nil)
+	if errors.Is(err, config.ErrValueNotFound) {
+		return nil, nil
+	} else if err != nil {
+		return nil, ErrComponentManager.WithError(err, "E0431")
+	}
+	var identifiers []string
+	switch value := value.(type) {
+	case nil:
+		return nil, nil
+	case string:
+		identifiers = strings.Split(value, ",")
+	case []string:
+		identifiers = value
+	case []any:
+		identifiers = make([]string, len(value))
+		for i, item := range value {
+			identifier, ok := item.(string)
+			if !ok {
+				return nil, ErrComponentManager.WithMessage("disabled component identifier must be a string", "E0432",
+					xerrors.NewOption("componego:config:key", DisabledComponentsConfigKey),
+					xerrors.NewOption("componego:config:value", item),
+				)
+			}
+			identifiers[i] = identifier
+		}
+	default:
+		return nil, ErrComponentManager.WithMessage("disabled components must be a list of strings", "E0434",
+			xerrors.NewOption("componego:config:key", DisabledComponentsConfigKey),
+			xerrors.NewOption("componego:config:value", value),
+		)
+	}
+	result := make(map[string]bool, len(identifiers))
+	for _, identifier := range identifiers {
+		if identifier = strings.TrimSpace(identifier); identifier != "" {
+			result[identifier] = true
+		}
+	}
+	return result, nil
+}
+
+func checkCondition(env componego.Environment, item *stackItem) (bool, error) {
+	component, ok := item.component.(componego.ComponentCondition)
+	if !ok {
+		return true, nil
+	}
+	isActive, err := component.ComponentCondition(env)
+	if err != nil {
+		return false, ErrComponentCondition.WithError(err, "E0423",
+			xerrors.NewOption("componego:component:component", component),
+		)
+	}
+	// The conditions of all wrapped components have already been checked, so we use the original component.
+	for {
+		wrapper, ok := item.component.(interface{ unwrap() componego.Component })
+		if !ok {
+			break
+		}
+		item.component = wrapper.unwrap()
+	}
+	return isActive, nil
+}
+
+func isOverride(component componego.Component, replaced componego.Component) bool {
+	// The same component can be returned by several components. This is not an override.
+	return component.ComponentVersion() != replaced.ComponentVersion() ||
+		reflect.TypeOf(component) != reflect.TypeOf(replaced)
+}
+
+func getParentComponents(item *stackItem) []componego.Component {
+	components := make([]componego.Component, 0, 3)
+	for parent := item.parent; parent != nil; parent = parent.parent {
+		components = append(components, parent.component)
+	}
+	utils.Reverse(components)
+	return components
+}
+
+func getComponentPath(parents []componego.Component, component componego.Component) string {
+	result := ""
+	for _, parent := range parents {
+		result += parent.ComponentIdentifier() + "@" + parent.ComponentVersion() + " > "
+	}
+	return result + component.ComponentIdentifier() + "@" + component.ComponentVersion()
+}
+
 func getCyclicDependencyProvider(stackItem *stackItem) func() any {
 	return func() any {
 		components := make([]componego.Component, 0, 5)
@@ -136,7 +530,7 @@ func getCyclicDependencyProvider(stackItem *stackItem) func() any {
 	}
 }
 
-func getSortedComponents(componentStack []*stackItem, componentMap map[string]*stackItem) []componego.Component {
+func getSortedComponents(componentStack []*stackItem, componentMap map[string]*stackItem) []*stackItem {
 	// The map does not guarantee the order,
 	// but it is important for us that the order in which the components are initialized is always the same.
 	sort.Slice(componentStack, func(i, j int) bool {
@@ -144,7 +538,7 @@ func getSortedComponents(componentStack []*stackItem, componentMap map[string]*s
 	})
 	// However, even this cannot guarantee that the order of the components will be correct.
 	// We do additional sorting based on dependencies between components.
-	result := make([]componego.Component, 0, len(componentMap))
+	result := make([]*stackItem, 0, len(componentMap))
 	// Tracks items that have been visited during traversal to prevent revisiting.
 	visited := make(map[string]bool, len(componentMap))
 	// Tracks items that have been fully processed.
@@ -152,7 +546,8 @@ func getSortedComponents(componentStack []*stackItem, componentMap map[string]*s
 	for isProcessed := true; len(componentStack) > 0; isProcessed = true {
 		stackItem := componentStack[len(componentStack)-1]
 		for _, identifier := range stackItem.children {
-			if visited[identifier] {
+			// Inactive child components are not present in the map.
+			if visited[identifier] || componentMap[identifier] == nil {
 				continue
 			}
 			visited[identifier] = true
@@ -166,16 +561,117 @@ func getSortedComponents(componentStack []*stackItem, componentMap map[string]*s
 		componentStack = componentStack[:len(componentStack)-1]
 		if !processed[stackItem.identifier] {
 			processed[stackItem.identifier] = true
-			result = append(result, stackItem.component)
+			result = append(result, stackItem)
 		}
 	}
 	return result
 }
 
+// sortBySoftOrder changes the order of components so that components are placed after the components they should follow.
+// The previous order is changed only where necessary.
+func sortBySoftOrder(items []*stackItem, componentMap map[string]*stackItem) ([]*stackItem, error) {
+	hasSoftOrder := false
+	for _, item := range items {
+		component, ok := item.component.(componego.ComponentAfter)
+		if !ok {
+			continue
+		}
+		identifiers, err := component.ComponentAfter()
+		if err != nil {
+			return nil, ErrComponentManager.WithError(err, "E0424",
+				xerrors.NewOption("componego:component:component", component),
+			)
+		}
+		for _, identifier := range identifiers {
+			// Components that are not active are ignored.
+			if identifier != item.identifier && componentMap[identifier] != nil {
+				item.after = append(item.after, identifier)
+				hasSoftOrder = true
+			}
+		}
+	}
+	if !hasSoftOrder {
+		return items, nil
+	}
+	result := make([]*stackItem, 0, len(items))
+	processed := make(map[string]bool, len(items))
+	isReady := func(item *stackItem) bool {
+		for _, identifier := range item.getPreviousIdentifiers(componentMap) {
+			if !processed[identifier] {
+				return false
+			}
+		}
+		return true
+	}
+	for len(result) < len(items) {
+		var readyItem *stackItem
+		// We always take the first ready component to keep the previous order as much as possible.
+		for _, item := range items {
+			if !processed[item.identifier] && isReady(item) {
+				readyItem = item
+				break
+			}
+		}
+		if readyItem == nil {
+			return nil, ErrCyclicDependencies.WithOptions("E0425",
+				xerrors.NewCallableOption("component:cyclicDependencies", getSoftCyclicDependencyProvider(items, processed, componentMap)),
+			)
+		}
+		processed[readyItem.identifier] = true
+		result = append(result, readyItem)
+	}
+	return result, nil
+}
+
+func getSoftCyclicDependencyProvider(items []*stackItem, processed map[string]bool, componentMap map[string]*stackItem) func() any {
+	return func() any {
+		var item *stackItem
+		for _, item = range items {
+			if !processed[item.identifier] {
+				break
+			}
+		}
+		// Each unprocessed component has at least one unprocessed previous component, so we always find a cycle.
+		path := make([]*stackItem, 0, 5)
+		positions := make(map[string]int, 5)
+		for {
+			if position, ok := positions[item.identifier]; ok {
+				path = append(path[position:], item)
+				break
+			}
+			positions[item.identifier] = len(path)
+			path = append(path, item)
+			for _, identifier := range item.getPreviousIdentifiers(componentMap) {
+				if !processed[identifier] {
+					item = componentMap[identifier]
+					break
+				}
+			}
+		}
+		components := make([]componego.Component, len(path))
+		for i, item := range path {
+			components[i] = item.component
+		}
+		return components
+	}
+}
+
 type stackItem struct {
 	identifier string
 	component  componego.Component
 	parent     *stackItem
 	children   []string
+	after      []string
 	position   int
 }
+
+// getPreviousIdentifiers returns identifiers of active components that must be placed before the current component.
+func (s *stackItem) getPreviousIdentifiers(componentMap map[string]*stackItem) []string {
+	result := make([]string, 0, len(s.children)+len(s.after))
+	for _, identifier := range s.children {
+		if componentMap[identifier] != nil {
+			result = append(result, identifier)
+		}
+	}
+	return append(result, s.after...)
+}
@@ -125,4 +125,21 @@ func TestFactory(t *testing.T) {
 			require.ErrorIs(t, componentItem3.(componego.ComponentStop).ComponentStop(expectedEnv, err2), err2)
 		})
 	})
+
+	t.Run("implemented interfaces", func(t *testing.T) {
+		factory := component.NewFactory("test", "0.0.1")
+		_, ok := component.Implements[componego.ComponentHealth](factory.Build())
+		require.False(t, ok)
+		factory.SetComponentHealth(func(_ context.Context) error {
+			return nil
+		})
+		componentItem := factory.Build()
+		_, ok = component.Implements[componego.ComponentHealth](componentItem)
+		require.True(t, ok)
+		_, ok = component.Implements[componego.ComponentLiveness](componentItem)
+		require.False(t, ok)
+		// Interfaces without functions are always implemented.
+		_, ok = component.Implements[componego.ComponentMetadata](componentItem)
+		require.True(t, ok)
+	})
 }
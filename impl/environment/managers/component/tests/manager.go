@@ -17,9 +17,13 @@ limitations under the License.
 package tests
 
 import (
+	"context"
 	"errors"
+	"io"
 
 	"github.com/componego/componego"
+	"github.com/componego/componego/impl/application"
+	"github.com/componego/componego/impl/environment"
 	"github.com/componego/componego/impl/environment/managers/component"
 	"github.com/componego/componego/internal/testing"
 	"github.com/componego/componego/internal/testing/require"
@@ -320,6 +324,92 @@ func ComponentManagerTester[T testing.TRun[T]](
 		})
 	})
 
+	t.Run("soft order", func(t T) {
+		t.Run("after active components", func(t T) {
+			initAndCompareComponents(t, factory, []treeItem{
+				{
+					name:    "component1",
+					version: "0.0.1",
+					after:   []string{"component3", "component4"}, // component4 is not active.
+				},
+				{
+					name:    "component2",
+					version: "0.0.1",
+				},
+				{
+					name:    "component3",
+					version: "0.0.1",
+					children: []treeItem{
+						{
+							name:    "component5",
+							version: "0.0.1",
+						},
+					},
+				},
+			}, []string{
+				"component2@0.0.1",
+				"component5@0.0.1",
+				"component3@0.0.1",
+				"component1@0.0.1",
+			}, nil)
+		})
+
+		t.Run("after child components", func(t T) {
+			initAndCompareComponents(t, factory, []treeItem{
+				{
+					name:    "component1",
+					version: "0.0.1",
+					children: []treeItem{
+						{
+							name:    "component2",
+							version: "0.0.1",
+							after:   []string{"component3"},
+						},
+						{
+							name:    "component3",
+							version: "0.0.1",
+						},
+					},
+				},
+			}, []string{
+				"component3@0.0.1",
+				"component2@0.0.1",
+				"component1@0.0.1",
+			}, nil)
+		})
+
+		t.Run("cycle between soft and hard order", func(t T) {
+			initAndCompareComponents(t, factory, []treeItem{
+				{
+					name:    "component1",
+					version: "0.0.1",
+					children: []treeItem{
+						{
+							name:    "component2",
+							version: "0.0.1",
+							after:   []string{"component1"},
+						},
+					},
+				},
+			}, nil, component.ErrCyclicDependencies)
+		})
+
+		t.Run("cycle between soft orders", func(t T) {
+			initAndCompareComponents(t, factory, []treeItem{
+				{
+					name:    "component1",
+					version: "0.0.1",
+					after:   []string{"component2"},
+				},
+				{
+					name:    "component2",
+					version: "0.0.1",
+					after:   []string{"component1"},
+				},
+			}, nil, component.ErrCyclicDependencies)
+		})
+	})
+
 	t.Run("cycle detection", func(t T) {
 		t.Run("with same version", func(t T) {
 			initAndCompareComponents(t, factory, []treeItem{
@@ -440,9 +530,11 @@ func ComponentManagerTester[T testing.TRun[T]](
 }
 
 type treeItem struct {
-	name     string
-	version  string
-	children []treeItem
+	name        string
+	version     string
+	children    []treeItem
+	after       []string
+	constraints map[string]string
 }
 
 func createTree(items []treeItem) []componego.Component {
@@ -455,6 +547,16 @@ func createTree(items []treeItem) []componego.Component {
 				return createTree(savedItem.children), nil
 			})
 		}
+		if after := item.after; len(after) > 0 {
+			componentFactory.SetComponentAfter(func() ([]string, error) {
+				return after, nil
+			})
+		}
+		if constraints := item.constraints; len(constraints) > 0 {
+			componentFactory.SetComponentVersionConstraints(func() (map[string]string, error) {
+				return constraints, nil
+			})
+		}
 		result[index] = componentFactory.Build()
 	}
 	return result
@@ -468,6 +570,11 @@ func getComponentsIdentifiers(components []componego.Component) []string {
 	return result
 }
 
+func createEnvironment(manager componego.ComponentProvider, appMode componego.ApplicationMode, writer io.Writer) componego.Environment {
+	appIO := application.NewIO(nil, writer, writer)
+	return environment.New(context.Background(), nil, appIO, appMode, nil, manager, nil)
+}
+
 func initAndCompareComponents(t testing.T, factory managerFactory, components []treeItem, expectedIdentifier []string, expectedError error) {
 	manager, initializer := factory()
 	err := initializer(createTree(components))
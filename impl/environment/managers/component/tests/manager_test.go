@@ -17,13 +17,21 @@ limitations under the License.
 package tests
 
 import (
+	"bytes"
+	"context"
 	"errors"
+	"io"
 	"testing"
 
 	"github.com/componego/componego"
 	"github.com/componego/componego/impl/application"
+	"github.com/componego/componego/impl/environment"
 	"github.com/componego/componego/impl/environment/managers/component"
+	"github.com/componego/componego/impl/environment/managers/config"
+	"github.com/componego/componego/impl/environment/managers/dependency"
+	"github.com/componego/componego/impl/environment/managers/dependency/container"
 	"github.com/componego/componego/internal/testing/require"
+	"github.com/componego/componego/internal/testing/types"
 	xerrorsTests "github.com/componego/componego/libs/xerrors/tests"
 )
 
@@ -118,6 +126,470 @@ func TestComponentManager(t *testing.T) {
 	})
 }
 
+func TestSoftOrderCycleError(t *testing.T) {
+	manager, initializer := component.NewManagerWithPolicy(component.AllowConflicts)
+	err := initializer(createEnvironment(manager, componego.TestMode, io.Discard), createTree([]treeItem{
+		{
+			name:    "component1",
+			version: "0.0.1",
+			after:   []string{"component3"},
+		},
+		{
+			name:    "component2",
+			version: "0.0.1",
+		},
+		{
+			name:    "component3",
+			version: "0.0.1",
+			children: []treeItem{
+				{
+					name:    "component4",
+					version: "0.0.1",
+					after:   []string{"component1"},
+				},
+			},
+		},
+	}))
+	require.ErrorIs(t, err, component.ErrCyclicDependencies)
+	cyclicDependencies := xerrorsTests.GetOptionsByKey(t, err, "component:cyclicDependencies")
+	require.NotPanics(t, func() {
+		require.Equal(t, []string{
+			"component1@0.0.1",
+			"component3@0.0.1",
+			"component4@0.0.1",
+			"component1@0.0.1",
+		}, getComponentsIdentifiers(cyclicDependencies.([]componego.Component)))
+	})
+}
+
+func TestVersionConstraints(t *testing.T) {
+	t.Run("satisfied constraints", func(t *testing.T) {
+		manager, initializer := component.NewManagerWithPolicy(component.AllowConflicts)
+		err := initializer(createEnvironment(manager, componego.TestMode, io.Discard), createTree([]treeItem{
+			{
+				name:    "component1",
+				version: "1.0.0",
+				constraints: map[string]string{
+					"component2": ">= 1.2.0, < 2",
+					"component3": "^1.0.0", // This component is not active.
+				},
+				children: []treeItem{
+					{
+						name:    "component2",
+						version: "1.2.5",
+					},
+				},
+			},
+		}))
+		require.NoError(t, err)
+		require.Equal(t, []string{
+			"component2@1.2.5",
+			"component1@1.0.0",
+		}, getComponentsIdentifiers(manager.Components()))
+	})
+
+	t.Run("constraints are checked after rewrites", func(t *testing.T) {
+		manager, initializer := component.NewManagerWithPolicy(component.AllowConflicts)
+		err := initializer(createEnvironment(manager, componego.TestMode, io.Discard), createTree([]treeItem{
+			{
+				name:    "component1",
+				version: "1.0.0",
+				constraints: map[string]string{
+					"component2": "~1.2",
+				},
+				children: []treeItem{
+					{
+						name:    "component2",
+						version: "1.2.0",
+					},
+				},
+			},
+			{
+				name:    "component2", // replace component2@1.2.0
+				version: "2.0.0",
+			},
+			{
+				name:    "component3",
+				version: "0.0.1",
+				constraints: map[string]string{
+					"component2": "^1.0",
+					"component1": "1.0.0",
+				},
+			},
+		}))
+		require.ErrorIs(t, err, component.ErrVersionConflict)
+		require.Len(t, manager.Components(), 0)
+		conflicts := xerrorsTests.GetOptionsByKey(t, err, "componego:component:versionConflicts")
+		require.NotPanics(t, func() {
+			require.Equal(t, []string{
+				"component1@1.0.0 requires component2 ~1.2, but found 2.0.0",
+				"component3@0.0.1 requires component2 ^1.0, but found 2.0.0",
+			}, []string{
+				conflicts.([]*component.VersionConflict)[0].String(),
+				conflicts.([]*component.VersionConflict)[1].String(),
+			})
+		})
+	})
+
+	t.Run("invalid version of the found component", func(t *testing.T) {
+		manager, initializer := component.NewManagerWithPolicy(component.AllowConflicts)
+		err := initializer(createEnvironment(manager, componego.TestMode, io.Discard), createTree([]treeItem{
+			{
+				name:    "component1",
+				version: "1.0.0",
+				constraints: map[string]string{
+					"component2": ">= 1",
+				},
+			},
+			{
+				name:    "component2",
+				version: "latest",
+			},
+		}))
+		require.ErrorIs(t, err, component.ErrVersionConflict)
+	})
+
+	t.Run("invalid constraint", func(t *testing.T) {
+		manager, initializer := component.NewManagerWithPolicy(component.AllowConflicts)
+		err := initializer(createEnvironment(manager, componego.TestMode, io.Discard), createTree([]treeItem{
+			{
+				name:    "component1",
+				version: "1.0.0",
+				constraints: map[string]string{
+					"component1": ">= x",
+				},
+			},
+		}))
+		require.ErrorIs(t, err, component.ErrVersionConstraint)
+	})
+}
+
+func TestComponentOverrides(t *testing.T) {
+	components := []treeItem{
+		{
+			name:    "component1",
+			version: "0.0.1",
+			children: []treeItem{
+				{
+					name:    "component2",
+					version: "0.0.1",
+				},
+				{
+					name:    "component3",
+					version: "0.0.1",
+				},
+			},
+		},
+		{
+			name:    "component4",
+			version: "0.0.1",
+			children: []treeItem{
+				{
+					name:    "component3", // the same component as component3 inside component1
+					version: "0.0.1",
+				},
+			},
+		},
+		{
+			name:    "component2", // replace component2@0.0.1
+			version: "0.0.2",
+		},
+	}
+
+	t.Run("allow conflicts", func(t *testing.T) {
+		buffer := &bytes.Buffer{}
+		manager, initializer := component.NewManagerWithPolicy(component.AllowConflicts)
+		env := createEnvironment(manager, componego.DeveloperMode, buffer)
+		require.NoError(t, initializer(env, createTree(components)))
+		require.Equal(t, 0, buffer.Len())
+		overrides, err := component.GetOverrides(env)
+		require.NoError(t, err)
+		require.Len(t, overrides, 1)
+		require.Equal(t, "component2", overrides[0].Identifier)
+		require.Equal(t, "component2@0.0.2", getComponentsIdentifiers([]componego.Component{overrides[0].Component})[0])
+		require.Len(t, overrides[0].ComponentParents, 0)
+		require.Equal(t, []string{"component1@0.0.1"}, getComponentsIdentifiers(overrides[0].ReplacedParents))
+		require.Equal(t, "component1@0.0.1 > component2@0.0.1 was replaced by component2@0.0.2", overrides[0].String())
+	})
+
+	t.Run("warn about conflicts", func(t *testing.T) {
+		for _, appMode := range []componego.ApplicationMode{componego.DeveloperMode, componego.ProductionMode} {
+			buffer := &bytes.Buffer{}
+			manager, initializer := component.NewManagerWithPolicy(component.WarnConflicts)
+			require.NoError(t, initializer(createEnvironment(manager, appMode, buffer), createTree(components)))
+			require.Len(t, manager.Components(), 4)
+			if appMode == componego.DeveloperMode {
+				require.Contains(t, buffer.String(), "component1@0.0.1 > component2@0.0.1 was replaced by component2@0.0.2")
+			} else {
+				require.Equal(t, 0, buffer.Len())
+			}
+		}
+	})
+
+	t.Run("forbid conflicts", func(t *testing.T) {
+		manager, initializer := component.NewManagerWithPolicy(component.ForbidConflicts)
+		err := initializer(createEnvironment(manager, componego.TestMode, io.Discard), createTree(components))
+		require.ErrorIs(t, err, component.ErrIdentifierConflict)
+		require.Len(t, manager.Components(), 0)
+		overrides := xerrorsTests.GetOptionsByKey(t, err, "componego:component:overrides")
+		require.NotPanics(t, func() {
+			require.Len(t, overrides.([]*component.Override), 1)
+		})
+		// The same component in different places is not a conflict.
+		manager, initializer = component.NewManagerWithPolicy(component.ForbidConflicts)
+		err = initializer(createEnvironment(manager, componego.TestMode, io.Discard), createTree(components[:2]))
+		require.NoError(t, err)
+	})
+}
+
+func TestComponentConditions(t *testing.T) {
+	createComponents := func() []componego.Component {
+		child := component.NewFactory("component4", "0.0.1")
+		parent := component.NewFactory("component3", "0.0.2")
+		parent.SetComponentCondition(component.ConfigEnabled("components.component3"))
+		parent.SetComponentComponents(func() ([]componego.Component, error) {
+			return []componego.Component{
+				child.Build(),
+				// Inactive child component.
+				component.When(component.InMode(componego.TestMode), component.NewFactory("component5", "0.0.1").Build()),
+			}, nil
+		})
+		return []componego.Component{
+			component.NewFactory("component1", "0.0.1").Build(),
+			component.When(component.InMode(componego.DeveloperMode), component.NewFactory("component2", "0.0.1").Build()),
+			parent.Build(),
+			// This component cannot replace component1@0.0.1 because it is inactive.
+			component.When(component.InMode(componego.TestMode), component.NewFactory("component1", "0.0.2").Build()),
+		}
+	}
+	initComponents := func(appMode componego.ApplicationMode, settings map[string]any) ([]string, error) {
+		configProvider, configInitializer := config.NewManager()
+		manager, initializer := component.NewManagerWithPolicy(component.ForbidConflicts)
+		appIO := application.NewIO(nil, io.Discard, io.Discard)
+		env := environment.New(context.Background(), nil, appIO, appMode, configProvider, manager, nil)
+		require.NoError(t, configInitializer(env, settings))
+		err := initializer(env, createComponents())
+		return getComponentsIdentifiers(manager.Components()), err
+	}
+
+	t.Run("all components are active", func(t *testing.T) {
+		identifiers, err := initComponents(componego.DeveloperMode, map[string]any{
+			"components.component3": "true",
+		})
+		require.NoError(t, err)
+		require.Equal(t, []string{
+			"component1@0.0.1",
+			"component2@0.0.1",
+			"component4@0.0.1",
+			"component3@0.0.2",
+		}, identifiers)
+	})
+
+	t.Run("inactive components are ignored with their child components", func(t *testing.T) {
+		identifiers, err := initComponents(componego.ProductionMode, map[string]any{
+			"components": map[string]any{
+				"component3": false,
+			},
+		})
+		require.NoError(t, err)
+		require.Equal(t, []string{
+			"component1@0.0.1",
+		}, identifiers)
+		identifiers, err = initComponents(componego.ProductionMode, nil)
+		require.NoError(t, err)
+		require.Equal(t, []string{
+			"component1@0.0.1",
+		}, identifiers)
+	})
+
+	t.Run("active component replaces another component", func(t *testing.T) {
+		_, err := initComponents(componego.TestMode, nil)
+		require.ErrorIs(t, err, component.ErrIdentifierConflict)
+	})
+
+	t.Run("active components are unwrapped", func(t *testing.T) {
+		original := component.NewFactory("component1", "0.0.1").Build()
+		manager, initializer := component.NewManagerWithPolicy(component.AllowConflicts)
+		env := createEnvironment(manager, componego.ProductionMode, io.Discard)
+		err := initializer(env, []componego.Component{
+			component.When(component.InMode(componego.ProductionMode), original),
+		})
+		require.NoError(t, err)
+		require.Len(t, manager.Components(), 1)
+		require.Same(t, original, manager.Components()[0])
+	})
+
+	t.Run("condition returns an error", func(t *testing.T) {
+		expectedErr := errors.New("error")
+		manager, initializer := component.NewManagerWithPolicy(component.AllowConflicts)
+		err := initializer(createEnvironment(manager, componego.ProductionMode, io.Discard), []componego.Component{
+			component.When(func(_ componego.Environment) (bool, error) {
+				return true, expectedErr
+			}, component.NewFactory("component1", "0.0.1").Build()),
+		})
+		require.ErrorIs(t, err, expectedErr)
+		require.ErrorIs(t, err, component.ErrComponentCondition)
+		require.Len(t, manager.Components(), 0)
+	})
+}
+
+func TestDescribe(t *testing.T) {
+	componentFactory := component.NewFactory("component5", "0.0.1")
+	componentFactory.SetComponentMetadata(componego.Metadata{
+		Description: "component with metadata",
+		Tags:        []string{"tag"},
+		Owner:       "owner",
+	})
+	componentFactory.SetComponentConfigDefaults(func() (string, map[string]any, error) {
+		return "namespace", map[string]any{
+			"key1": 1,
+			"key2": map[string]any{
+				"key3": 3,
+			},
+			"key.4": 4,
+		}, nil
+	})
+	componentFactory.SetComponentConfigSchema(func() (string, []*componego.ConfigKey, error) {
+		return "namespace", []*componego.ConfigKey{
+			{Key: "key1", Type: config.TypeInt},
+			{Key: "key5", Type: config.TypeString},
+		}, nil
+	})
+	componentFactory.SetComponentDependencies(func() ([]componego.Dependency, error) {
+		return []componego.Dependency{
+			func() (*types.AStruct, *types.BStruct, error) {
+				return nil, nil, nil
+			},
+		}, nil
+	})
+	components := append(createTree([]treeItem{
+		{
+			name:    "component1",
+			version: "0.0.1",
+			children: []treeItem{
+				{
+					name:    "component2",
+					version: "0.0.1",
+				},
+				{
+					name:    "component3",
+					version: "0.0.1",
+					after:   []string{"component4", "unknown"},
+				},
+			},
+		},
+		{
+			name:    "component4",
+			version: "0.0.1",
+			children: []treeItem{
+				{
+					name:    "component2",
+					version: "0.0.1",
+				},
+			},
+		},
+	}), componentFactory.Build())
+	manager, initializer := component.NewManagerWithPolicy(component.AllowConflicts)
+	dependencyInvoker, dependencyInitializer := dependency.NewManagerWithDependencies()
+	env := environment.New(context.Background(), nil, application.NewIO(nil, io.Discard, io.Discard), componego.TestMode, nil, manager, dependencyInvoker)
+	require.NoError(t, initializer(env, components))
+	dependencies, err := dependency.CollectDependencies(env)
+	require.NoError(t, err)
+	containerInstance, _ := container.New(len(dependencies.List))
+	require.NoError(t, dependencyInitializer(containerInstance, dependencies))
+	description, err := component.Describe(env)
+	require.NoError(t, err)
+	require.Equal(t, []string{"component1", "component4", "component5"}, description.Roots)
+	require.Equal(t, getComponentsIdentifiers(manager.Components()), getComponentsIdentifiers(func() []componego.Component {
+		result := make([]componego.Component, len(description.Nodes))
+		for i, node := range description.Nodes {
+			result[i] = node.Component
+		}
+		return result
+	}()))
+	nodes := make(map[string]*component.Node, len(description.Nodes))
+	for _, node := range description.Nodes {
+		nodes[node.Identifier] = node
+	}
+	require.Equal(t, []string{"component2", "component3"}, nodes["component1"].Children)
+	// Parents are sorted in the order of component initialization.
+	require.Equal(t, []string{"component4", "component1"}, nodes["component2"].Parents)
+	require.Equal(t, []string{"component4"}, nodes["component3"].After)
+	require.Len(t, nodes["component1"].Parents, 0)
+	require.Equal(t, "component with metadata", nodes["component5"].Metadata.Description)
+	require.Equal(t, []string{"tag"}, nodes["component5"].Metadata.Tags)
+	require.Equal(t, []string{"*types.AStruct", "*types.BStruct"}, nodes["component5"].Dependencies)
+	require.Equal(t, []string{"namespace.key1", "namespace.key2.key3", "namespace.key5", `namespace.key\.4`}, nodes["component5"].ConfigKeys)
+	require.Equal(t, "component1@0.0.1\n"+
+		"  component2@0.0.1\n"+
+		"  component3@0.0.1\n"+
+		"component4@0.0.1\n"+
+		"  component2@0.0.1 (see above)\n"+
+		"component5@0.0.1 - component with metadata\n", description.String())
+}
+
+func TestDisabledComponents(t *testing.T) {
+	components := []treeItem{
+		{
+			name:    "component1",
+			version: "0.0.1",
+			children: []treeItem{
+				{
+					name:    "component2",
+					version: "0.0.1",
+				},
+				{
+					name:    "component3",
+					version: "0.0.1",
+				},
+			},
+		},
+		{
+			name:    "component4",
+			version: "0.0.1",
+			children: []treeItem{
+				{
+					name:    "component3",
+					version: "0.0.1",
+				},
+			},
+		},
+	}
+	createEnvironmentWithConfig := func(manager componego.ComponentProvider, disabled any) componego.Environment {
+		configProvider, configInitializer := config.NewManager()
+		appIO := application.NewIO(nil, io.Discard, io.Discard)
+		env := environment.New(context.Background(), nil, appIO, componego.TestMode, configProvider, manager, nil)
+		require.NoError(t, configInitializer(env, map[string]any{
+			component.DisabledComponentsConfigKey: disabled,
+		}))
+		return env
+	}
+
+	t.Run("disable components with unreferenced subtrees", func(t *testing.T) {
+		for _, disabled := range []any{"component1, unknown", []string{"component1"}, []any{"component1"}} {
+			manager, initializer := component.NewManagerWithPolicy(component.AllowConflicts)
+			require.NoError(t, initializer(createEnvironmentWithConfig(manager, disabled), createTree(components)))
+			require.Equal(t, []string{"component3@0.0.1", "component4@0.0.1"}, getComponentsIdentifiers(manager.Components()))
+		}
+	})
+
+	t.Run("disabled component is a hard dependency", func(t *testing.T) {
+		manager, initializer := component.NewManagerWithPolicy(component.AllowConflicts)
+		err := initializer(createEnvironmentWithConfig(manager, "component3"), createTree(components))
+		require.ErrorIs(t, err, component.ErrDisabledComponent)
+		require.Len(t, manager.Components(), 0)
+	})
+
+	t.Run("invalid value", func(t *testing.T) {
+		for _, disabled := range []any{123, []any{"component1", 123}} {
+			manager, initializer := component.NewManagerWithPolicy(component.AllowConflicts)
+			err := initializer(createEnvironmentWithConfig(manager, disabled), createTree(components))
+			require.ErrorIs(t, err, component.ErrComponentManager)
+			require.NotErrorIs(t, err, component.ErrDisabledComponent)
+		}
+	})
+}
+
 func TestExtractComponents(t *testing.T) {
 	t.Run("application without components", func(t *testing.T) {
 		app := &testApplication{}
@@ -0,0 +1,409 @@
+/*
+Copyright 2024-present Volodymyr Konstanchuk and contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/componego/componego"
+	"github.com/componego/componego/impl/processors"
+	"github.com/componego/componego/libs/type-cast"
+	"github.com/componego/componego/libs/xerrors"
+)
+
+var (
+	ErrBind      = ErrConfigManager.WithMessage("config bind error", "E0351")
+	ErrBindField = ErrBind.WithMessage("invalid config value", "E0352")
+)
+
+// BindProcessors is an interface that describes named processors of a struct populated by Bind.
+// The names can be used in the "processors" tag of the struct fields in addition to the built-in names.
+type BindProcessors interface {
+	ConfigProcessors() map[string]componego.Processor
+}
+
+// builtInProcessors are processors that can be used in the "processors" tag of any struct.
+var builtInProcessors = map[string]func() componego.Processor{
+	"toBool":     processors.ToBool,
+	"isBool":     processors.IsBool,
+	"toInt64":    processors.ToInt64,
+	"toFloat64":  processors.ToFloat64,
+	"toString":   processors.ToString,
+	"isRequired": processors.IsRequired,
+}
+
+var (
+	durationType = reflect.TypeOf(time.Duration(0))
+	timeType     = reflect.TypeOf(time.Time{})
+)
+
+// Bind populates a struct with the configuration values with the prefix. An empty prefix means the whole configuration.
+// The struct fields support the following tags:
+//
+//	config:"key"            the key relative to the prefix. By default, it is the field name in lower camel case. Use "-" to skip the field.
+//	default:"value"         the value used if the configuration does not contain the key.
+//	required:"true"         the configuration must contain the key.
+//	processors:"toInt64,x"  processors that are called one by one before the value is converted to the field type.
+//
+// Nested structs, pointers, slices, maps, time.Duration and time.Time are supported.
+// All field errors are returned at once. Each error contains the full key of the field.
+func Bind[T any](env componego.Environment, prefix string) (T, error) {
+	var result T
+	reflectType := reflect.TypeOf(result)
+	if reflectType == nil || reflectType.Kind() != reflect.Struct {
+		return result, ErrBind.WithMessage(fmt.Sprintf("type %T is not a struct", result), "E0353")
+	}
+	processor := &binder{
+		prefix:      prefix,
+		reflectType: reflectType,
+	}
+	var (
+		value any
+		err   error
+	)
+	if manager, ok := env.ConfigProvider().(*manager); ok && prefix == "" {
+		// An empty key is an ordinary key for ConfigValue, so the whole configuration is passed to the binder directly.
+		values, ok := manager.extractValue(prefix)
+		value, err = manager.processValue(prefix, values, ok, processor)
+	} else {
+		value, err = env.ConfigProvider().ConfigValue(prefix, processor)
+	}
+	if err != nil {
+		return result, err
+	}
+	return value.(T), nil
+}
+
+// BindOrPanic is the same as Bind, but it panics if there is an error.
+func BindOrPanic[T any](env componego.Environment, prefix string) T {
+	result, err := Bind[T](env, prefix)
+	if err != nil {
+		panic(err)
+	}
+	return result
+}
+
+// binder is a processor that converts the configuration values into the struct.
+// Because it is a processor, the struct is checked again when the configuration is reloaded.
+type binder struct {
+	di          componego.DependencyInvoker `componego:"inject"`
+	prefix      string
+	reflectType reflect.Type
+	errs        []error
+}
+
+// ProcessData belongs to interface componego.Processor.
+func (b *binder) ProcessData(value any) (any, error) {
+	b.errs = make([]error, 0)
+	result := reflect.New(b.reflectType).Elem()
+	b.bindStruct(b.prefix, value, result)
+	if len(b.errs) > 0 {
+		return nil, errors.Join(b.errs...)
+	}
+	return result.Interface(), nil
+}
+
+func (b *binder) addError(key string, err error) {
+	b.errs = append(b.errs, ErrBindField.WithError(fmt.Errorf("%s: %w", key, err), "E0354",
+		xerrors.NewOption("componego:config:key", key),
+	))
+}
+
+func (b *binder) bindStruct(prefix string, value any, result reflect.Value) {
+	var values map[string]any
+	switch castedValue := value.(type) {
+	case nil:
+		// Default values of the fields are used.
+	case map[string]any:
+		values = castedValue
+	default:
+		b.addError(keyOrRoot(prefix), fmt.Errorf("expected an object, got %T", value))
+		return
+	}
+	var namedProcessors map[string]componego.Processor
+	if instance, ok := reflect.New(result.Type()).Interface().(BindProcessors); ok {
+		namedProcessors = instance.ConfigProcessors()
+	}
+	reflectType := result.Type()
+	for i := 0; i < reflectType.NumField(); i++ {
+		field := reflectType.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		key, ok := field.Tag.Lookup("config")
+		if key == "-" {
+			continue
+		} else if !ok && field.Anonymous && field.Type.Kind() == reflect.Struct {
+			// Embedded structs without the tag use the same prefix.
+			b.bindStruct(prefix, value, result.Field(i))
+			continue
+		} else if key == "" {
+			key = toLowerCamelCase(field.Name)
+		}
+		fullKey := joinKey(prefix, key)
+		fieldValue, found := extractValue(values, key)
+		if !found {
+			fieldValue = nil
+		}
+		if defaultValue, ok := field.Tag.Lookup("default"); ok && fieldValue == nil {
+			fieldValue = defaultValue
+		}
+		if required, _ := strconv.ParseBool(field.Tag.Get("required")); required && fieldValue == nil {
+			b.addError(fullKey, errors.New("the value is required"))
+			continue
+		}
+		if names := field.Tag.Get("processors"); names != "" {
+			var err error
+			if fieldValue, err = b.process(fieldValue, names, namedProcessors); err != nil {
+				b.addError(fullKey, err)
+				continue
+			}
+		}
+		b.bindValue(fullKey, fieldValue, result.Field(i))
+	}
+}
+
+func (b *binder) process(value any, names string, namedProcessors map[string]componego.Processor) (any, error) {
+	for _, name := range strings.Split(names, ",") {
+		name = strings.TrimSpace(name)
+		processor, ok := namedProcessors[name]
+		if !ok {
+			factory, ok := builtInProcessors[name]
+			if !ok {
+				return nil, fmt.Errorf("unknown processor '%s'", name)
+			}
+			processor = factory()
+		}
+		if b.di != nil {
+			// Injecting a dependency into an object before calling the object's methods.
+			if err := b.di.PopulateFields(processor); err != nil {
+				return nil, err
+			}
+		}
+		var err error
+		if value, err = processor.ProcessData(value); err != nil {
+			return nil, err
+		}
+	}
+	return value, nil
+}
+
+// bindValue converts the value to the type of the result and sets it.
+func (b *binder) bindValue(key string, value any, result reflect.Value) {
+	reflectType := result.Type()
+	if value == nil {
+		if reflectType.Kind() == reflect.Struct && reflectType != timeType {
+			// Nested structs can contain default values.
+			b.bindStruct(key, nil, result)
+		}
+		return
+	}
+	switch {
+	case reflectType == durationType:
+		b.bindDuration(key, value, result)
+	case reflectType == timeType:
+		b.bindTime(key, value, result)
+	case reflectType.Kind() == reflect.Pointer:
+		pointer := reflect.New(reflectType.Elem())
+		b.bindValue(key, value, pointer.Elem())
+		result.Set(pointer)
+	case reflectType.Kind() == reflect.Struct:
+		b.bindStruct(key, value, result)
+	case reflectType.Kind() == reflect.Slice:
+		b.bindSlice(key, value, result)
+	case reflectType.Kind() == reflect.Map:
+		b.bindMap(key, value, result)
+	case reflectType.Kind() == reflect.Interface:
+		reflectValue := reflect.ValueOf(value)
+		if !reflectValue.Type().AssignableTo(reflectType) {
+			b.addError(key, fmt.Errorf("cannot use %T as %s", value, reflectType))
+			return
+		}
+		result.Set(reflectValue)
+	default:
+		b.bindScalar(key, value, result)
+	}
+}
+
+func (b *binder) bindDuration(key string, value any, result reflect.Value) {
+	switch castedValue := value.(type) {
+	case time.Duration:
+		result.SetInt(int64(castedValue))
+	case string:
+		duration, err := time.ParseDuration(castedValue)
+		if err != nil {
+			b.addError(key, err)
+			return
+		}
+		result.SetInt(int64(duration))
+	default:
+		b.addError(key, fmt.Errorf("expected a duration string such as \"5s\", got %T", value))
+	}
+}
+
+func (b *binder) bindTime(key string, value any, result reflect.Value) {
+	switch castedValue := value.(type) {
+	case time.Time:
+		result.Set(reflect.ValueOf(castedValue))
+	case string:
+		parsedTime, err := time.Parse(time.RFC3339, castedValue)
+		if err != nil {
+			b.addError(key, err)
+			return
+		}
+		result.Set(reflect.ValueOf(parsedTime))
+	default:
+		b.addError(key, fmt.Errorf("expected a time in RFC 3339 format, got %T", value))
+	}
+}
+
+func (b *binder) bindSlice(key string, value any, result reflect.Value) {
+	var items []any
+	switch castedValue := value.(type) {
+	case []any:
+		items = castedValue
+	case string:
+		// Values from environment variables and command line arguments are comma-separated strings.
+		items = make([]any, 0)
+		if strings.TrimSpace(castedValue) != "" {
+			for _, item := range strings.Split(castedValue, ",") {
+				items = append(items, strings.TrimSpace(item))
+			}
+		}
+	default:
+		reflectValue := reflect.ValueOf(value)
+		if reflectValue.Kind() != reflect.Slice && reflectValue.Kind() != reflect.Array {
+			b.addError(key, fmt.Errorf("expected a list, got %T", value))
+			return
+		}
+		items = make([]any, reflectValue.Len())
+		for i := range items {
+			items[i] = reflectValue.Index(i).Interface()
+		}
+	}
+	slice := reflect.MakeSlice(result.Type(), len(items), len(items))
+	for i, item := range items {
+		b.bindValue(joinKey(key, strconv.Itoa(i)), item, slice.Index(i))
+	}
+	result.Set(slice)
+}
+
+func (b *binder) bindMap(key string, value any, result reflect.Value) {
+	reflectType := result.Type()
+	if reflectType.Key().Kind() != reflect.String {
+		b.addError(key, fmt.Errorf("map keys must be strings, got %s", reflectType.Key()))
+		return
+	}
+	values, ok := value.(map[string]any)
+	if !ok {
+		b.addError(key, fmt.Errorf("expected an object, got %T", value))
+		return
+	}
+	resultMap := reflect.MakeMapWithSize(reflectType, len(values))
+	for itemKey, item := range values {
+		itemValue := reflect.New(reflectType.Elem()).Elem()
+		b.bindValue(joinKey(key, EscapeKey(itemKey)), item, itemValue)
+		resultMap.SetMapIndex(reflect.ValueOf(itemKey).Convert(reflectType.Key()), itemValue)
+	}
+	result.Set(resultMap)
+}
+
+func (b *binder) bindScalar(key string, value any, result reflect.Value) {
+	reflectValue := reflect.ValueOf(value)
+	if reflectValue.Type().AssignableTo(result.Type()) {
+		result.Set(reflectValue)
+		return
+	}
+	var err error
+	switch result.Kind() {
+	case reflect.Bool:
+		var castedValue bool
+		if castedValue, err = type_cast.ToBool(value); err == nil {
+			result.SetBool(castedValue)
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		var castedValue int64
+		if castedValue, err = type_cast.ToInt64(value); err == nil {
+			if result.OverflowInt(castedValue) {
+				err = fmt.Errorf("value %d overflows %s", castedValue, result.Type())
+			} else {
+				result.SetInt(castedValue)
+			}
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		var castedValue int64
+		if castedValue, err = type_cast.ToInt64(value); err == nil {
+			if castedValue < 0 || result.OverflowUint(uint64(castedValue)) {
+				err = fmt.Errorf("value %d overflows %s", castedValue, result.Type())
+			} else {
+				result.SetUint(uint64(castedValue))
+			}
+		}
+	case reflect.Float32, reflect.Float64:
+		var castedValue float64
+		if castedValue, err = type_cast.ToFloat64(value); err == nil {
+			if result.OverflowFloat(castedValue) {
+				err = fmt.Errorf("value %v overflows %s", castedValue, result.Type())
+			} else {
+				result.SetFloat(castedValue)
+			}
+		}
+	case reflect.String:
+		var castedValue string
+		if castedValue, err = type_cast.ToString(value); err == nil {
+			result.SetString(castedValue)
+		}
+	default:
+		err = fmt.Errorf("unsupported field type %s", result.Type())
+	}
+	if err != nil {
+		b.addError(key, err)
+	}
+}
+
+func joinKey(prefix string, key string) string {
+	if prefix == "" {
+		return key
+	}
+	return prefix + delimiter + key
+}
+
+func keyOrRoot(key string) string {
+	if key == "" {
+		return "<root>"
+	}
+	return key
+}
+
+// toLowerCamelCase converts the field name into the configuration key, for example, ReadTimeout becomes readTimeout and URLPath becomes urlPath.
+func toLowerCamelCase(name string) string {
+	runes := []rune(name)
+	for i := 0; i < len(runes) && unicode.IsUpper(runes[i]); i++ {
+		// The last upper case letter of an abbreviation belongs to the next word.
+		if i > 0 && i+1 < len(runes) && unicode.IsLower(runes[i+1]) {
+			break
+		}
+		runes[i] = unicode.ToLower(runes[i])
+	}
+	return string(runes)
+}
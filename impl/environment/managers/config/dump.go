@@ -0,0 +1,296 @@
+/*
+Copyright 2024-present Volodymyr Konstanchuk and contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/componego/componego"
+	"github.com/componego/componego/internal/utils"
+	"github.com/componego/componego/libs/debug"
+	"github.com/componego/componego/libs/xerrors"
+)
+
+// Formats of the configuration dump.
+const (
+	// DumpJSON is the configuration as a JSON object.
+	DumpJSON = "json"
+	// DumpFlat is one line key=value for each value.
+	DumpFlat = "flat"
+	// DumpTree is the configuration rendered by debug.RenderVariable.
+	DumpTree = "tree"
+)
+
+// SecretMask replaces the values of secret keys in dumps.
+const SecretMask = "******"
+
+var (
+	ErrDump = ErrConfigManager.WithMessage("config dump error", "E0390")
+)
+
+// DefaultSecretPatterns are parts of key names that mark values as secret. The comparison is case-insensitive.
+var DefaultSecretPatterns = []string{"password", "token", "secret"}
+
+// DumpOptions are options of the configuration dump.
+type DumpOptions struct {
+	// Format is one of DumpJSON, DumpFlat or DumpTree. JSON format is used if the format is empty.
+	Format string
+	// SecretPatterns are parts of key names that mark values as secret. DefaultSecretPatterns are used if it is nil.
+	// Keys that are marked as secret in the configuration schema are always masked.
+	SecretPatterns []string
+	// Origins adds the origins of values to the dump.
+	Origins bool
+}
+
+// Dump returns the effective configuration of the application after merging, interpolation and default values.
+// The values of secret keys are replaced with SecretMask.
+// If the options are nil, JSON format is used, and the origins of values are added in developer mode.
+func Dump(env componego.Environment, options *DumpOptions) (string, error) {
+	if options == nil {
+		options = &DumpOptions{
+			Origins: env.ApplicationMode() == componego.DeveloperMode,
+		}
+	}
+	secretPatterns := options.SecretPatterns
+	if secretPatterns == nil {
+		secretPatterns = DefaultSecretPatterns
+	}
+	schema, err := GetSchema(env.Components())
+	if err != nil {
+		return "", err
+	}
+	secrets := &secretMatcher{
+		patterns: make([]string, len(secretPatterns)),
+		keys:     make(map[string]struct{}),
+	}
+	for i, pattern := range secretPatterns {
+		secrets.patterns[i] = strings.ToLower(pattern)
+	}
+	for _, schemaKey := range schema {
+		if schemaKey.Secret {
+			secrets.keys[schemaKey.Key] = struct{}{}
+		}
+	}
+	origins := GetOrigins(env)
+	secrets.addInterpolatedSecrets(origins)
+	value, _ := AllValues(env)
+	settings, _ := secrets.mask("", value).(map[string]any)
+	if settings == nil {
+		settings = make(map[string]any)
+	}
+	if !options.Origins {
+		origins = nil
+	} else {
+		for i, origin := range origins {
+			if secrets.isSecret(origin.Key) && origin.RawValue != nil {
+				maskedOrigin := *origin
+				maskedOrigin.RawValue = SecretMask
+				origins[i] = &maskedOrigin
+			}
+		}
+	}
+	switch options.Format {
+	case DumpJSON, "":
+		return dumpJSON(settings, origins, options.Origins)
+	case DumpFlat:
+		return dumpFlat(settings, origins), nil
+	case DumpTree:
+		return dumpTree(settings, origins, options.Origins), nil
+	}
+	return "", ErrDump.WithMessage(fmt.Sprintf("unknown dump format %q", options.Format), "E0391",
+		xerrors.NewOption("componego:config:format", options.Format),
+	)
+}
+
+type secretMatcher struct {
+	patterns []string
+	keys     map[string]struct{}
+}
+
+// isSecret returns true if the key or any of its parents is secret.
+func (s *secretMatcher) isSecret(configKey string) bool {
+	segments, err := parseKey(configKey)
+	if err != nil {
+		return false
+	}
+	for i, segment := range segments {
+		if _, ok := s.keys[joinSegments(segments[:i+1])]; ok {
+			return true
+		}
+		name := strings.ToLower(segment.name)
+		for _, pattern := range s.patterns {
+			if strings.Contains(name, pattern) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// addInterpolatedSecrets marks the values that contain secret variables as secret,
+// for example, a connection string that contains ${CONFIG:db.password}.
+// The variables of values are known only from the origins.
+func (s *secretMatcher) addInterpolatedSecrets(origins []*Origin) {
+	for _, origin := range origins {
+		for _, variable := range origin.Variables {
+			_, name, _ := strings.Cut(variable, ":")
+			if s.isSecret(name) {
+				s.keys[origin.Key] = struct{}{}
+				break
+			}
+		}
+	}
+}
+
+// mask returns a copy of the value in which the values of secret keys are replaced.
+// Maps and lists of any type are copied as map[string]any and []any, so all their values are checked.
+// Structs are converted in the same way as in JSON format. Values that cannot be converted are masked.
+func (s *secretMatcher) mask(prefix string, value any) any {
+	if prefix != "" && s.isSecret(prefix) {
+		if value == nil {
+			return nil
+		}
+		return SecretMask
+	}
+	switch castedValue := value.(type) {
+	case nil, string, bool, int, int64, float64:
+		return value
+	case map[string]any:
+		result := make(map[string]any, len(castedValue))
+		for key, item := range castedValue {
+			result[key] = s.mask(joinKey(prefix, EscapeKey(key)), item)
+		}
+		return result
+	case []any:
+		result := make([]any, len(castedValue))
+		for i, item := range castedValue {
+			result[i] = s.mask(joinKey(prefix, strconv.Itoa(i)), item)
+		}
+		return result
+	}
+	reflectValue := reflect.ValueOf(value)
+	switch reflectValue.Kind() {
+	case reflect.Map:
+		result := make(map[string]any, reflectValue.Len())
+		iterator := reflectValue.MapRange()
+		for iterator.Next() {
+			key := fmt.Sprint(iterator.Key().Interface())
+			result[key] = s.mask(joinKey(prefix, EscapeKey(key)), iterator.Value().Interface())
+		}
+		return result
+	case reflect.Slice, reflect.Array:
+		if reflectValue.Type().Elem().Kind() == reflect.Uint8 {
+			// Bytes do not contain keys.
+			return value
+		}
+		result := make([]any, reflectValue.Len())
+		for i := range result {
+			result[i] = s.mask(joinKey(prefix, strconv.Itoa(i)), reflectValue.Index(i).Interface())
+		}
+		return result
+	case reflect.Pointer, reflect.Interface:
+		if reflectValue.IsNil() {
+			return nil
+		}
+		return s.mask(prefix, reflectValue.Elem().Interface())
+	case reflect.Struct:
+		data, err := json.Marshal(value)
+		if err != nil {
+			return SecretMask
+		}
+		var result any
+		if err = json.Unmarshal(data, &result); err != nil {
+			return SecretMask
+		}
+		return s.mask(prefix, result)
+	}
+	return value
+}
+
+func dumpJSON(settings map[string]any, origins []*Origin, withOrigins bool) (string, error) {
+	var value any = settings
+	if withOrigins {
+		value = struct {
+			Config  map[string]any `json:"config"`
+			Origins []*Origin      `json:"origins"`
+		}{
+			Config:  settings,
+			Origins: origins,
+		}
+	}
+	data, err := json.MarshalIndent(value, "", utils.Indent)
+	if err != nil {
+		return "", ErrDump.WithError(err, "E0392")
+	}
+	return string(data) + "\n", nil
+}
+
+func dumpFlat(settings map[string]any, origins []*Origin) string {
+	originMap := make(map[string]*Origin, len(origins))
+	for _, origin := range origins {
+		originMap[origin.Key] = origin
+	}
+	keys := make([]string, 0)
+	collectLeafKeys("", settings, &keys)
+	builder := &strings.Builder{}
+	for _, key := range keys {
+		value, _ := extractValue(settings, key)
+		builder.WriteString(key)
+		builder.WriteByte('=')
+		builder.WriteString(formatFlatValue(value))
+		if origin, ok := originMap[key]; ok {
+			builder.WriteString(" # ")
+			builder.WriteString(origin.String())
+		}
+		builder.WriteByte('\n')
+	}
+	return builder.String()
+}
+
+// formatFlatValue returns strings as they are and other values in JSON format.
+func formatFlatValue(value any) string {
+	if value, ok := value.(string); ok {
+		return value
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(data)
+}
+
+func dumpTree(settings map[string]any, origins []*Origin, withOrigins bool) string {
+	var value any = settings
+	if withOrigins {
+		originMap := make(map[string]string, len(origins))
+		for _, origin := range origins {
+			originMap[origin.Key] = origin.String()
+		}
+		value = map[string]any{
+			"config":  settings,
+			"origins": originMap,
+		}
+	}
+	return debug.RenderVariable(value, &debug.VariableConfig{
+		Indent:     utils.Indent,
+		UseNewLine: true,
+	}) + "\n"
+}
@@ -18,8 +18,6 @@ package config
 
 import (
 	"fmt"
-	"os"
-	"regexp"
 
 	"github.com/componego/componego"
 )
@@ -42,36 +40,3 @@ func GetOrPanic[T any](configKey string, processor componego.Processor, env comp
 	}
 	return result
 }
-
-func ProcessVariables(settings map[string]any) (err error) {
-	// ${ENV:VARIABLE_NAME} or ${ENV:VARIABLE_NAME|DEFAULT_VALUE}
-	variableRegex := regexp.MustCompile(`\$\{ENV:([a-zA-Z0-9_]+)(\|([a-zA-Z0-9_]+))?}`)
-	for key, value := range settings {
-		valueAsString, ok := value.(string)
-		if !ok {
-			// We process nested values since the configuration nesting level can be any.
-			valueAsMap, ok := value.(map[string]any)
-			if !ok {
-				continue
-			}
-			if err = ProcessVariables(valueAsMap); err != nil {
-				return err
-			}
-			continue
-		}
-		settings[key] = variableRegex.ReplaceAllStringFunc(valueAsString, func(match string) string {
-			matches := variableRegex.FindStringSubmatch(match)
-			if envValue := os.Getenv(matches[1]); len(envValue) != 0 {
-				return envValue
-			} else if len(matches[3]) > 0 {
-				return matches[3] // default value
-			}
-			err = fmt.Errorf("environment variable '%s' not found", matches[1])
-			return match
-		})
-		if err != nil {
-			break
-		}
-	}
-	return err
-}
@@ -0,0 +1,413 @@
+/*
+Copyright 2024-present Volodymyr Konstanchuk and contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/componego/componego/libs/xerrors"
+)
+
+var (
+	ErrInterpolation = ErrConfigInit.WithMessage("config interpolation error", "E0370")
+)
+
+// VariableSource is an interface that describes a source of variables for configuration values.
+type VariableSource interface {
+	// LookupVariable returns the value of the variable.
+	// The second value is false if the variable does not exist. In this case, the default value of the variable is used.
+	LookupVariable(name string) (any, bool, error)
+}
+
+// VariableSourceFunc is a function that implements interface VariableSource.
+type VariableSourceFunc func(name string) (any, bool, error)
+
+// LookupVariable belongs to interface VariableSource.
+func (f VariableSourceFunc) LookupVariable(name string) (any, bool, error) {
+	return f(name)
+}
+
+// Interpolator replaces variables inside configuration values.
+//
+// The format of a variable is ${SOURCE:NAME} or ${SOURCE:NAME|DEFAULT_VALUE}.
+// The default value can contain any characters except an unpaired closing brace, including other variables.
+// Use $${ to write ${ without replacement.
+// If a string contains only one variable, the value of the variable keeps its type.
+// Variables without a source, variables of unknown sources and unclosed variables are left unchanged
+// unless the strict mode is enabled. Variables that are not found and have no default value always return an error.
+//
+// The following sources are available by default:
+//
+//	${ENV:NAME}        the value of the environment variable. An empty variable is treated as a missing one.
+//	${CONFIG:KEY}      the value of another configuration key. Variables inside that value are replaced too.
+//
+// Files can be read only if the source returned by NewFileVariableSource is added, for example, as ${FILE:PATH}.
+type Interpolator struct {
+	sources map[string]VariableSource
+	strict  bool
+}
+
+// NewInterpolator returns a new interpolator with the default sources.
+func NewInterpolator() *Interpolator {
+	return &Interpolator{
+		sources: map[string]VariableSource{
+			"ENV": VariableSourceFunc(lookupEnvVariable),
+		},
+	}
+}
+
+// AddSource adds or replaces a source of variables. The name CONFIG is reserved.
+func (i *Interpolator) AddSource(name string, source VariableSource) *Interpolator {
+	i.sources[name] = source
+	return i
+}
+
+// SetStrict enables or disables the strict mode.
+// In the strict mode, variables that cannot be replaced return an error instead of being left unchanged.
+func (i *Interpolator) SetStrict(strict bool) *Interpolator {
+	i.strict = strict
+	return i
+}
+
+// Process replaces variables in all values of the configuration, including values inside arrays.
+// All errors are returned at once. Each error contains the key of the value.
+func (i *Interpolator) Process(settings map[string]any) error {
+	_, err := i.process(settings)
+	return err
+}
+
+// ProcessSettings replaces variables in the values of the settings like Process.
+// The raw values and the replaced variables are added to the origins of the values.
+func (i *Interpolator) ProcessSettings(settings *Settings) error {
+	changes, err := i.process(settings.Values)
+	if err != nil {
+		return err
+	}
+	origins := settings.getOrigins()
+	for _, change := range changes {
+		origins.setInterpolation(change.configKey, change.rawValue, change.variables)
+	}
+	return nil
+}
+
+// process replaces variables in the configuration and returns the values in which variables were replaced.
+func (i *Interpolator) process(settings map[string]any) ([]*interpolationChange, error) {
+	state := &interpolation{
+		sources:  i.sources,
+		strict:   i.strict,
+		settings: settings,
+	}
+	keys := make([]string, 0, len(settings))
+	for key := range settings {
+		keys = append(keys, key)
+	}
+	// The keys are sorted so that errors are always returned in the same order.
+	sort.Strings(keys)
+	result := make(map[string]any, len(settings))
+	errs := make([]error, 0)
+	for _, key := range keys {
+		value, valueErrs := state.resolveValue(EscapeKey(key), settings[key], []string{key})
+		result[key] = value
+		for _, valueErr := range valueErrs {
+			errs = append(errs, ErrInterpolation.WithError(valueErr, "E0372",
+				xerrors.NewOption("componego:config:key", valueErr.path),
+			))
+		}
+	}
+	if len(errs) > 0 {
+		return nil, ErrInterpolation.WithError(errors.Join(errs...), "E0371")
+	}
+	// The values are replaced only after all of them are resolved,
+	// so references to other keys always see the original values.
+	for key, value := range result {
+		settings[key] = value
+	}
+	return state.changes, nil
+}
+
+// ProcessVariables replaces variables in the configuration using the interpolator with the default sources.
+func ProcessVariables(settings map[string]any) error {
+	return NewInterpolator().Process(settings)
+}
+
+type interpolation struct {
+	sources  map[string]VariableSource
+	strict   bool
+	settings map[string]any
+	// variables are the variables of the current value.
+	variables []string
+	changes   []*interpolationChange
+}
+
+// interpolationChange describes a value in which variables were replaced.
+type interpolationChange struct {
+	configKey string
+	rawValue  string
+	variables []string
+}
+
+// pathError is an error of the value with the key.
+type pathError struct {
+	path string
+	err  error
+}
+
+func (p *pathError) Error() string {
+	return p.path + ": " + p.err.Error()
+}
+
+func (p *pathError) Unwrap() error {
+	return p.err
+}
+
+// resolveValue returns a copy of the value in which all variables are replaced.
+// The path is the key of the value in the key syntax of ConfigValue.
+// The stack contains the keys that are being resolved and is used to find cyclic references.
+func (s *interpolation) resolveValue(path string, value any, stack []string) (any, []*pathError) {
+	switch castedValue := value.(type) {
+	case string:
+		// The stack contains only the top-level key if the value is not resolved as a reference of another value.
+		isReference := len(stack) > 1
+		if !isReference {
+			s.variables = make([]string, 0)
+		}
+		result, err := s.resolveString(castedValue, stack)
+		if err != nil {
+			return value, []*pathError{{path, err}}
+		}
+		if resultString, ok := result.(string); !isReference && (!ok || resultString != castedValue) {
+			s.changes = append(s.changes, &interpolationChange{
+				configKey: path,
+				rawValue:  castedValue,
+				variables: s.variables,
+			})
+		}
+		return result, nil
+	case map[string]any:
+		result := make(map[string]any, len(castedValue))
+		errs := make([]*pathError, 0)
+		keys := make([]string, 0, len(castedValue))
+		for key := range castedValue {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			item, itemErrs := s.resolveValue(joinKey(path, EscapeKey(key)), castedValue[key], stack)
+			result[key] = item
+			errs = append(errs, itemErrs...)
+		}
+		return result, errs
+	case []any:
+		result := make([]any, len(castedValue))
+		errs := make([]*pathError, 0)
+		for i, item := range castedValue {
+			var itemErrs []*pathError
+			result[i], itemErrs = s.resolveValue(joinKey(path, strconv.Itoa(i)), item, stack)
+			errs = append(errs, itemErrs...)
+		}
+		return result, errs
+	default:
+		return value, nil
+	}
+}
+
+func (s *interpolation) resolveString(value string, stack []string) (any, error) {
+	if !strings.Contains(value, "${") {
+		return value, nil
+	}
+	builder := &strings.Builder{}
+	for i := 0; i < len(value); {
+		if strings.HasPrefix(value[i:], "$${") {
+			builder.WriteString("${")
+			i += 3
+			continue
+		}
+		if !strings.HasPrefix(value[i:], "${") {
+			builder.WriteByte(value[i])
+			i++
+			continue
+		}
+		end := findClosingBrace(value, i+2)
+		if end < 0 {
+			if s.strict {
+				return nil, fmt.Errorf("unclosed variable %q", value[i:])
+			}
+			builder.WriteString(value[i:])
+			break
+		}
+		result, ok, err := s.resolveVariable(value[i+2:end], stack)
+		if err != nil {
+			return nil, err
+		} else if !ok {
+			// The variable is not ours, so it is left unchanged.
+			builder.WriteString(value[i : end+1])
+			i = end + 1
+			continue
+		}
+		if i == 0 && end == len(value)-1 {
+			// The value consists of one variable, so the type of the variable is kept.
+			return result, nil
+		}
+		if result != nil {
+			_, _ = fmt.Fprint(builder, result)
+		}
+		i = end + 1
+	}
+	return builder.String(), nil
+}
+
+// resolveVariable returns the value of the variable.
+// The second value is false if the variable has no source or an unknown source and the strict mode is disabled.
+func (s *interpolation) resolveVariable(expression string, stack []string) (any, bool, error) {
+	sourceName, name, ok := strings.Cut(expression, ":")
+	if !ok {
+		if s.strict {
+			return nil, false, fmt.Errorf("invalid variable %q", "${"+expression+"}")
+		}
+		return nil, false, nil
+	}
+	source, ok := s.sources[sourceName]
+	if !ok && sourceName != "CONFIG" {
+		if s.strict {
+			return nil, false, fmt.Errorf("unknown variable source %q", sourceName)
+		}
+		return nil, false, nil
+	}
+	name, defaultValue, hasDefault := cutDefaultValue(name)
+	s.variables = append(s.variables, sourceName+":"+name)
+	var (
+		value any
+		found bool
+		err   error
+	)
+	if sourceName == "CONFIG" {
+		value, found, err = s.lookupConfigVariable(name, stack)
+	} else {
+		value, found, err = source.LookupVariable(name)
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	if found {
+		return value, true, nil
+	}
+	if hasDefault {
+		value, err = s.resolveString(defaultValue, stack)
+		return value, err == nil, err
+	}
+	return nil, false, fmt.Errorf("variable %q not found", sourceName+":"+name)
+}
+
+// lookupConfigVariable returns the value of another configuration key in which all variables are replaced.
+func (s *interpolation) lookupConfigVariable(configKey string, stack []string) (any, bool, error) {
+	for i, key := range stack {
+		if key == configKey {
+			cycle := append(append(make([]string, 0, len(stack)-i+1), stack[i:]...), configKey)
+			return nil, false, fmt.Errorf("cyclic reference %s", strings.Join(cycle, " -> "))
+		}
+	}
+	value, ok := extractValue(s.settings, configKey)
+	if !ok {
+		return nil, false, nil
+	}
+	// A copy of the stack is used because the stack is shared between the values of the same level.
+	nextStack := append(stack[:len(stack):len(stack)], configKey)
+	result, errs := s.resolveValue(configKey, value, nextStack)
+	if len(errs) > 0 {
+		joinedErrs := make([]error, len(errs))
+		for i, err := range errs {
+			joinedErrs[i] = err
+		}
+		return nil, false, errors.Join(joinedErrs...)
+	}
+	return result, true, nil
+}
+
+// findClosingBrace returns the index of the brace that closes the variable which starts before the index.
+// Nested variables are skipped.
+func findClosingBrace(value string, start int) int {
+	depth := 1
+	for i := start; i < len(value); i++ {
+		switch {
+		case strings.HasPrefix(value[i:], "${"):
+			depth++
+			i++
+		case value[i] == '}':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// cutDefaultValue splits the name of the variable and the default value by the first pipe outside nested variables.
+func cutDefaultValue(name string) (string, string, bool) {
+	depth := 0
+	for i := 0; i < len(name); i++ {
+		switch {
+		case strings.HasPrefix(name[i:], "${"):
+			depth++
+			i++
+		case name[i] == '}':
+			depth--
+		case name[i] == '|' && depth == 0:
+			return name[:i], name[i+1:], true
+		}
+	}
+	return name, "", false
+}
+
+func lookupEnvVariable(name string) (any, bool, error) {
+	if value := os.Getenv(name); value != "" {
+		return value, true, nil
+	}
+	return nil, false, nil
+}
+
+// NewFileVariableSource returns a source of variables that reads files inside the directory.
+// The name of the variable is the path of the file relative to the directory.
+// The value is the contents of the file without the trailing line break.
+// Files outside the directory cannot be read, for example, through "..".
+func NewFileVariableSource(directory string) VariableSource {
+	return VariableSourceFunc(func(name string) (any, bool, error) {
+		filename := filepath.Join(directory, name)
+		if relativePath, err := filepath.Rel(directory, filename); err != nil ||
+			relativePath == ".." || strings.HasPrefix(relativePath, ".."+string(filepath.Separator)) {
+			return nil, false, fmt.Errorf("file %q is outside the directory %q", name, directory)
+		}
+		return lookupFileVariable(filename)
+	})
+}
+
+func lookupFileVariable(filename string) (any, bool, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, false, nil
+		}
+		return nil, false, err
+	}
+	return strings.TrimRight(string(data), "\r\n"), true, nil
+}
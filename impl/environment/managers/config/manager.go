@@ -17,7 +17,7 @@ limitations under the License.
 package config
 
 import (
-	"strings"
+	"sync"
 
 	"github.com/componego/componego"
 	"github.com/componego/componego/libs/xerrors"
@@ -33,24 +33,55 @@ var (
 	ErrConfigInit    = ErrConfigManager.WithMessage("config init error", "E0311")
 	ErrConfigGet     = ErrConfigManager.WithMessage("config get error", "E0312")
 	ErrValueNotFound = ErrConfigGet.WithMessage("config value not found", "E0313")
+	ErrConfigDefault = ErrConfigManager.WithMessage("config defaults error", "E0317")
 )
 
 type manager struct {
 	env          componego.Environment
+	mutex        sync.RWMutex
 	parsedConfig map[string]any
+	reload       *reloadState
+	provenance   *provenance
 }
 
 func NewManager() (componego.ConfigProvider, func(componego.Environment, map[string]any) error) {
-	m := &manager{}
+	m, initializer := newManager()
+	return m, func(env componego.Environment, parsedConfig map[string]any) error {
+		return initializer(env, NewSettings(parsedConfig))
+	}
+}
+
+// NewManagerWithSettings returns the config manager whose initializer receives the configuration with the origins of values.
+func NewManagerWithSettings() (componego.ConfigProvider, func(componego.Environment, *Settings) error) {
+	return newManager()
+}
+
+func newManager() (*manager, func(componego.Environment, *Settings) error) {
+	m := &manager{
+		reload:     newReloadState(),
+		provenance: newProvenance(),
+	}
 	return m, m.initialize
 }
 
 func (m *manager) ConfigValue(configKey string, processor componego.Processor) (any, error) {
-	value, ok := m.extractValue(configKey)
+	value, ok := m.lookupValue(configKey)
+	return m.processValue(configKey, value, ok, processor)
+}
+
+// processValue returns the value found by the key or the result of the processor.
+func (m *manager) processValue(configKey string, value any, ok bool, processor componego.Processor) (any, error) {
 	if processor == nil {
 		if ok {
 			return value, nil
 		}
+		// The key is checked only if the value is not found, because the configuration can contain flat keys.
+		// The processor can return a default value, so the key is not checked if the processor is passed.
+		if _, err := parseKey(configKey); err != nil && configKey != "" {
+			return nil, ErrInvalidKey.WithError(err, "E0381",
+				xerrors.NewOption("componego:config:key", configKey),
+			)
+		}
 		return nil, ErrValueNotFound.WithOptions("E0314",
 			xerrors.NewOption("componego:config:key", configKey),
 		)
@@ -58,41 +89,224 @@ func (m *manager) ConfigValue(configKey string, processor componego.Processor) (
 	// Injecting a dependency into an object before calling the object's methods.
 	err := m.env.DependencyInvoker().PopulateFields(processor)
 	if err == nil {
+		// The processor is used again to check the new configuration when it is reloaded.
+		m.reload.addProcessor(configKey, processor)
 		value, err = processor.ProcessData(value)
 		if err == nil {
+			if !ok && value != nil {
+				m.getProvenance().setIfAbsent(&Origin{
+					Key:    configKey,
+					Source: OriginProcessor,
+				})
+			}
 			return value, nil
 		}
 	}
-	return nil, ErrConfigGet.WithError(err, "E0315",
-		xerrors.NewOption("componego:config:key", configKey),
-	)
+	return nil, ErrConfigGet.WithError(err, "E0315", m.getOriginOptions(configKey)...)
+}
+
+// lookupValue returns the value by the key like extractValue.
+// Unlike extractValue, an empty key is an ordinary key, so it does not return the whole configuration.
+func (m *manager) lookupValue(configKey string) (any, bool) {
+	if configKey != "" {
+		return m.extractValue(configKey)
+	}
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	value, ok := m.parsedConfig[configKey]
+	return value, ok
 }
 
 func (m *manager) extractValue(configKey string) (any, bool) {
-	if value, ok := m.parsedConfig[configKey]; ok {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	return extractValue(m.parsedConfig, configKey)
+}
+
+// extractValue returns the value by the key. See parseKey for the syntax of the key.
+// The value is not found if the key passes through a value that is neither an object nor a list.
+func extractValue(parsedConfig map[string]any, configKey string) (any, bool) {
+	if configKey == "" {
+		// An empty key means the whole configuration.
+		return parsedConfig, parsedConfig != nil
+	}
+	// The configuration can contain keys with dots if it was not expanded.
+	if value, ok := parsedConfig[configKey]; ok {
 		return value, true
 	}
-	keys := strings.Split(configKey, delimiter)
-	var configValue any = m.parsedConfig
-	for _, key := range keys {
-		switch parsedConfig := configValue.(type) {
+	segments, err := parseKey(configKey)
+	if err != nil {
+		return nil, false
+	}
+	return lookupSegments(parsedConfig, segments)
+}
+
+// setDefaultValue sets the value by the key only if the configuration does not contain a value for this key.
+// The function returns the normalized key if the value is set.
+func setDefaultValue(parsedConfig map[string]any, configKey string, value any) (string, bool) {
+	if _, ok := extractValue(parsedConfig, configKey); ok {
+		return "", false
+	}
+	segments, err := parseKey(configKey)
+	if err != nil {
+		return "", false
+	}
+	for _, segment := range segments[:len(segments)-1] {
+		if segment.wildcard {
+			return "", false
+		}
+		switch nestedConfig := parsedConfig[segment.name].(type) {
 		case map[string]any:
-			if value, ok := parsedConfig[key]; ok {
-				configValue = value
-			} else {
-				return nil, false
-			}
+			parsedConfig = nestedConfig
+		case nil:
+			parsedConfig[segment.name] = make(map[string]any)
+			parsedConfig = parsedConfig[segment.name].(map[string]any)
+		default:
+			// The application configuration contains another value in this place.
+			return "", false
 		}
 	}
-	return configValue, true
+	lastSegment := segments[len(segments)-1]
+	if lastSegment.wildcard {
+		return "", false
+	}
+	parsedConfig[lastSegment.name] = value
+	return joinSegments(segments), true
 }
 
-func (m *manager) initialize(env componego.Environment, parsedConfig map[string]any) error {
+func (m *manager) initialize(env componego.Environment, settings *Settings) error {
 	m.env = env
-	m.parsedConfig = parsedConfig
+	m.parsedConfig = settings.Values
+	m.provenance = settings.getOrigins().copy()
 	return nil
 }
 
+// AllValues returns the whole configuration of the application.
+// The configuration is shared with the config manager, so it must not be modified.
+// The second value is false if the config provider of the environment is not the config manager.
+func AllValues(env componego.Environment) (map[string]any, bool) {
+	manager, ok := env.ConfigProvider().(*manager)
+	if !ok {
+		return nil, false
+	}
+	manager.mutex.RLock()
+	defer manager.mutex.RUnlock()
+	return manager.parsedConfig, true
+}
+
+func (m *manager) getProvenance() *provenance {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	return m.provenance
+}
+
+// applyDefaults adds the default values and remembers the source of each added value.
+func (m *manager) applyDefaults(namespace string, defaults map[string]any, source string) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if m.parsedConfig == nil {
+		m.parsedConfig = make(map[string]any)
+	}
+	for _, configKey := range applyDefaults(m.parsedConfig, namespace, defaults) {
+		m.provenance.set(&Origin{
+			Key:    configKey,
+			Source: source,
+		})
+	}
+}
+
+// ApplyComponentDefaults adds the default configuration of all active components to the configuration.
+// The values from the application configuration take precedence over the default values.
+func ApplyComponentDefaults(env componego.Environment) error {
+	return applyComponentDefaults(env, nil)
+}
+
+// applyComponentDefaults adds the default configuration to the manager.
+// The default values of the configuration schema are added too.
+// The config provider of the environment is used if the manager is nil.
+func applyComponentDefaults(env componego.Environment, target *manager) error {
+	for _, component := range env.Components() {
+		defaults, err := getComponentDefaults(component)
+		if err != nil {
+			return ErrConfigDefault.WithError(err, "E0318",
+				xerrors.NewOption("componego:config:component", component),
+			)
+		}
+		if len(defaults) == 0 {
+			continue
+		}
+		if target == nil {
+			manager, ok := env.ConfigProvider().(*manager)
+			if !ok {
+				return ErrConfigDefault.WithMessage("config provider does not support default values", "E0319",
+					xerrors.NewOption("componego:config:component", component),
+					xerrors.NewOption("componego:config:provider", env.ConfigProvider()),
+				)
+			}
+			target = manager
+		}
+		for _, item := range defaults {
+			target.applyDefaults(item.namespace, item.defaults, OriginDefault+component.ComponentIdentifier())
+		}
+	}
+	return nil
+}
+
+// getComponentDefaults returns the default values of the component grouped by namespace.
+// The values of ComponentConfigDefaults come first, so they take precedence over the values of the schema.
+func getComponentDefaults(component componego.Component) ([]componentDefaults, error) {
+	result := make([]componentDefaults, 0, 2)
+	if component, ok := component.(componego.ComponentConfigDefaults); ok {
+		namespace, defaults, err := component.ComponentConfigDefaults()
+		if err != nil {
+			return nil, err
+		}
+		if len(defaults) > 0 {
+			result = append(result, componentDefaults{namespace, defaults})
+		}
+	}
+	if component, ok := component.(componego.ComponentConfigSchema); ok {
+		namespace, keys, err := component.ComponentConfigSchema()
+		if err != nil {
+			return nil, err
+		}
+		defaults := make(map[string]any, len(keys))
+		for _, key := range keys {
+			if key != nil && key.Default != nil {
+				defaults[key.Key] = key.Default
+			}
+		}
+		if len(defaults) > 0 {
+			result = append(result, componentDefaults{namespace, defaults})
+		}
+	}
+	return result, nil
+}
+
+type componentDefaults struct {
+	namespace string
+	defaults  map[string]any
+}
+
+// applyDefaults adds the default values and returns the keys of the added values.
+func applyDefaults(parsedConfig map[string]any, prefix string, defaults map[string]any) []string {
+	addedKeys := make([]string, 0)
+	for key, value := range defaults {
+		if prefix != "" {
+			key = prefix + delimiter + key
+		}
+		// Nested values are added one by one so as not to replace the values of the application.
+		if nestedDefaults, ok := value.(map[string]any); ok && len(nestedDefaults) > 0 {
+			addedKeys = append(addedKeys, applyDefaults(parsedConfig, key, nestedDefaults)...)
+			continue
+		}
+		if addedKey, ok := setDefaultValue(parsedConfig, key, value); ok {
+			addedKeys = append(addedKeys, addedKey)
+		}
+	}
+	return addedKeys
+}
+
 func ParseConfig(env componego.Environment, options any) (map[string]any, error) {
 	if app, ok := env.Application().(componego.ApplicationConfigInit); ok {
 		parsedConfig, err := app.ApplicationConfigInit(env.ApplicationMode(), options)
@@ -103,3 +317,32 @@ func ParseConfig(env componego.Environment, options any) (map[string]any, error)
 	}
 	return nil, nil
 }
+
+// ApplicationSettingsInit is an interface that describes the process of obtaining configuration with the origins of values.
+// The driver calls this function instead of ApplicationConfigInit if the application implements this interface.
+type ApplicationSettingsInit interface {
+	// Application belongs to the application.
+	componego.Application
+	// ApplicationSettingsInit returns configuration for all application entities and the origins of the values.
+	ApplicationSettingsInit(appMode componego.ApplicationMode, options any) (*Settings, error)
+}
+
+// ParseSettings returns the configuration of the application with the origins of values.
+// The origins are unknown if the application implements only ApplicationConfigInit.
+func ParseSettings(env componego.Environment, options any) (*Settings, error) {
+	if app, ok := env.Application().(ApplicationSettingsInit); ok {
+		settings, err := app.ApplicationSettingsInit(env.ApplicationMode(), options)
+		if err != nil {
+			return nil, ErrConfigInit.WithError(err, "E0316")
+		}
+		if settings == nil {
+			return NewSettings(nil), nil
+		}
+		return settings, nil
+	}
+	parsedConfig, err := ParseConfig(env, options)
+	if err != nil {
+		return nil, err
+	}
+	return NewSettings(parsedConfig), nil
+}
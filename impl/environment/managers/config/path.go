@@ -0,0 +1,203 @@
+/*
+Copyright 2024-present Volodymyr Konstanchuk and contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"errors"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrInvalidKey = ErrConfigGet.WithMessage("invalid config key", "E0380")
+)
+
+// keySegment is a part of the configuration key between dots.
+type keySegment struct {
+	name     string
+	wildcard bool
+}
+
+// parseKey splits the configuration key into segments.
+//
+// The segments are separated by a dot. A segment is a key of an object or an index of a list, for example, servers.0.addr.
+// The segment * matches all values of an object or a list.
+// Use \. to write a dot inside a segment, \* to write an asterisk and \\ to write a backslash.
+func parseKey(configKey string) ([]keySegment, error) {
+	segments := make([]keySegment, 0, strings.Count(configKey, delimiter)+1)
+	builder := &strings.Builder{}
+	// A segment is escaped if it contains an escape sequence, so \* is not a wildcard.
+	escaped, segmentEscaped, empty := false, false, true
+	for i := 0; i < len(configKey); i++ {
+		char := configKey[i]
+		switch {
+		case escaped:
+			if char != '.' && char != '*' && char != '\\' {
+				return nil, errors.New("invalid escape sequence \\" + string(char))
+			}
+			builder.WriteByte(char)
+			escaped = false
+		case char == '\\':
+			escaped, segmentEscaped, empty = true, true, false
+		case char == '.':
+			if empty {
+				return nil, errors.New("empty segment")
+			}
+			segments = append(segments, newKeySegment(builder.String(), segmentEscaped))
+			builder.Reset()
+			segmentEscaped, empty = false, true
+		default:
+			builder.WriteByte(char)
+			empty = false
+		}
+	}
+	if escaped {
+		return nil, errors.New("unfinished escape sequence")
+	}
+	if empty {
+		return nil, errors.New("empty segment")
+	}
+	return append(segments, newKeySegment(builder.String(), segmentEscaped)), nil
+}
+
+func newKeySegment(name string, escaped bool) keySegment {
+	return keySegment{
+		name:     name,
+		wildcard: name == "*" && !escaped,
+	}
+}
+
+// EscapeKey escapes the dots, asterisks and backslashes, so the value can be used as one segment of a configuration key.
+func EscapeKey(segment string) string {
+	return strings.NewReplacer(`\`, `\\`, `.`, `\.`, `*`, `\*`).Replace(segment)
+}
+
+// JoinKey appends the segment to the configuration key. The segment is escaped, but the key is used as is.
+func JoinKey(configKey string, segment string) string {
+	return joinKey(configKey, EscapeKey(segment))
+}
+
+// lookupSegments returns the value by the segments of the key.
+// A wildcard returns a list of values of all children that contain the rest of the key.
+func lookupSegments(value any, segments []keySegment) (any, bool) {
+	if len(segments) == 0 {
+		return value, true
+	}
+	if segments[0].wildcard {
+		children, ok := getChildren(value)
+		if !ok {
+			return nil, false
+		}
+		result := make([]any, 0, len(children))
+		for _, child := range children {
+			if childValue, ok := lookupSegments(child, segments[1:]); ok {
+				result = append(result, childValue)
+			}
+		}
+		return result, true
+	}
+	child, ok := getChild(value, segments[0].name)
+	if !ok {
+		return nil, false
+	}
+	return lookupSegments(child, segments[1:])
+}
+
+// getChild returns a value of the object by the key or a value of the list by the index.
+// Other values do not have children.
+func getChild(value any, name string) (any, bool) {
+	switch castedValue := value.(type) {
+	case map[string]any:
+		child, ok := castedValue[name]
+		return child, ok
+	case []any:
+		if index, ok := parseIndex(name, len(castedValue)); ok {
+			return castedValue[index], true
+		}
+		return nil, false
+	}
+	reflectValue := reflect.ValueOf(value)
+	switch reflectValue.Kind() {
+	case reflect.Map:
+		if reflectValue.Type().Key().Kind() != reflect.String {
+			return nil, false
+		}
+		child := reflectValue.MapIndex(reflect.ValueOf(name).Convert(reflectValue.Type().Key()))
+		if !child.IsValid() {
+			return nil, false
+		}
+		return child.Interface(), true
+	case reflect.Slice, reflect.Array:
+		if index, ok := parseIndex(name, reflectValue.Len()); ok {
+			return reflectValue.Index(index).Interface(), true
+		}
+	}
+	return nil, false
+}
+
+// getChildren returns all values of the object sorted by key or all values of the list.
+func getChildren(value any) ([]any, bool) {
+	switch castedValue := value.(type) {
+	case map[string]any:
+		keys := make([]string, 0, len(castedValue))
+		for key := range castedValue {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		result := make([]any, len(keys))
+		for i, key := range keys {
+			result[i] = castedValue[key]
+		}
+		return result, true
+	case []any:
+		return castedValue, true
+	}
+	reflectValue := reflect.ValueOf(value)
+	switch reflectValue.Kind() {
+	case reflect.Map:
+		if reflectValue.Type().Key().Kind() != reflect.String {
+			return nil, false
+		}
+		keys := reflectValue.MapKeys()
+		sort.Slice(keys, func(i, j int) bool {
+			return keys[i].String() < keys[j].String()
+		})
+		result := make([]any, len(keys))
+		for i, key := range keys {
+			result[i] = reflectValue.MapIndex(key).Interface()
+		}
+		return result, true
+	case reflect.Slice, reflect.Array:
+		result := make([]any, reflectValue.Len())
+		for i := range result {
+			result[i] = reflectValue.Index(i).Interface()
+		}
+		return result, true
+	}
+	return nil, false
+}
+
+// parseIndex returns the index of the list. Only canonical numbers such as 0 and 12 are indexes.
+func parseIndex(name string, length int) (int, bool) {
+	index, err := strconv.Atoi(name)
+	if err != nil || index < 0 || index >= length || strconv.Itoa(index) != name {
+		return 0, false
+	}
+	return index, true
+}
@@ -0,0 +1,265 @@
+/*
+Copyright 2024-present Volodymyr Konstanchuk and contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/componego/componego"
+	"github.com/componego/componego/libs/xerrors"
+)
+
+// These are the sources of values that are not read by LoadSources.
+const (
+	// OriginApplication is the source of values that were returned by ApplicationConfigInit without LoadSources.
+	OriginApplication = "application"
+	// OriginProcessor is the source of values that were returned by a processor because the configuration does not contain them.
+	OriginProcessor = "processor"
+	// OriginDefault is the prefix of the source of the default values of a component, for example, "default:server".
+	OriginDefault = "default:"
+)
+
+// Origin describes where a configuration value came from.
+type Origin struct {
+	// Key is the configuration key of the value.
+	Key string `json:"key"`
+	// Source describes the source of the value, for example, "file:/app/config.json", "env:APP_SERVER__PORT" or "default:server".
+	Source string `json:"source"`
+	// RawValue is the value before variables were replaced. It is nil if the value does not contain variables.
+	RawValue any `json:"rawValue,omitempty"`
+	// Variables are the replaced variables, for example, "ENV:APP_PORT".
+	Variables []string `json:"variables,omitempty"`
+}
+
+// String belongs to interface fmt.Stringer.
+func (o *Origin) String() string {
+	if o.RawValue == nil {
+		return o.Source
+	}
+	result := fmt.Sprintf("%s (raw value: %q", o.Source, fmt.Sprint(o.RawValue))
+	if len(o.Variables) > 0 {
+		result += ", variables: " + strings.Join(o.Variables, ", ")
+	}
+	return result + ")"
+}
+
+// GetOrigin returns the origin of the configuration value by the key.
+// The origin of the closest parent is returned if the value is a part of a list or another value that has one origin.
+// The second value is false if the config provider does not track origins or the origin is unknown.
+func GetOrigin(env componego.Environment, configKey string) (*Origin, bool) {
+	manager, ok := env.ConfigProvider().(*manager)
+	if !ok {
+		return nil, false
+	}
+	return manager.getOrigin(configKey)
+}
+
+// GetOrigins returns the origins of all values of the configuration sorted by key.
+// Objects are not included, only their values.
+func GetOrigins(env componego.Environment) []*Origin {
+	manager, ok := env.ConfigProvider().(*manager)
+	if !ok {
+		return nil
+	}
+	manager.mutex.RLock()
+	keys := make([]string, 0)
+	collectLeafKeys("", manager.parsedConfig, &keys)
+	manager.mutex.RUnlock()
+	origins := make([]*Origin, 0, len(keys))
+	for _, key := range keys {
+		if origin, ok := manager.getOrigin(key); ok {
+			origins = append(origins, origin)
+		}
+	}
+	return origins
+}
+
+type provenance struct {
+	mutex   sync.RWMutex
+	origins map[string]*Origin
+}
+
+func newProvenance() *provenance {
+	return &provenance{
+		origins: make(map[string]*Origin),
+	}
+}
+
+// copy returns a copy of the origins.
+// The copy is used because the application can return the same settings each time they are read.
+func (p *provenance) copy() *provenance {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+	result := newProvenance()
+	for key, origin := range p.origins {
+		// Origins are not modified after they are added, so they can be shared.
+		result.origins[key] = origin
+	}
+	return result
+}
+
+func (p *provenance) get(configKey string) (*Origin, bool) {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+	origin, ok := p.origins[configKey]
+	return origin, ok
+}
+
+// set replaces the origin of the key and removes the origins of its nested values.
+func (p *provenance) set(origin *Origin) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	for key := range p.origins {
+		if strings.HasPrefix(key, origin.Key+delimiter) {
+			delete(p.origins, key)
+		}
+	}
+	p.origins[origin.Key] = origin
+}
+
+// setIfAbsent adds the origin only if the key does not have an origin yet.
+func (p *provenance) setIfAbsent(origin *Origin) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if _, ok := p.origins[origin.Key]; !ok {
+		p.origins[origin.Key] = origin
+	}
+}
+
+// merge adds all origins of another provenance.
+func (p *provenance) merge(other *provenance) {
+	other.mutex.RLock()
+	defer other.mutex.RUnlock()
+	for _, origin := range other.origins {
+		p.set(origin)
+	}
+}
+
+// setSource adds the source of all values with the prefix.
+// The function is called for each source in order, so the last source of the value wins.
+func (p *provenance) setSource(prefix string, value any, describe func(configKey string) string) {
+	if values, ok := value.(map[string]any); ok {
+		if prefix != "" {
+			// The object replaces the value of the previous source only if the previous value is not an object.
+			p.mutex.Lock()
+			delete(p.origins, prefix)
+			p.mutex.Unlock()
+		}
+		for key, value := range values {
+			p.setSource(joinKey(prefix, EscapeKey(key)), value, describe)
+		}
+		return
+	}
+	p.set(&Origin{
+		Key:    prefix,
+		Source: describe(prefix),
+	})
+}
+
+// setInterpolation adds the raw value and the replaced variables to the origin of the key.
+func (p *provenance) setInterpolation(configKey string, rawValue any, variables []string) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	origin := &Origin{
+		Key:    configKey,
+		Source: OriginApplication,
+	}
+	if prevOrigin, ok := p.origins[configKey]; ok {
+		// The origin is copied because it can be shared.
+		copiedOrigin := *prevOrigin
+		origin = &copiedOrigin
+	}
+	origin.RawValue = rawValue
+	origin.Variables = variables
+	p.origins[configKey] = origin
+}
+
+func (m *manager) getOrigin(configKey string) (*Origin, bool) {
+	m.mutex.RLock()
+	p := m.provenance
+	value, ok := extractValue(m.parsedConfig, configKey)
+	m.mutex.RUnlock()
+	if !ok {
+		// The value can be returned by a processor.
+		return p.get(configKey)
+	}
+	if origin, ok := p.get(configKey); ok {
+		return origin, true
+	}
+	segments, err := parseKey(configKey)
+	if err != nil {
+		return nil, false
+	}
+	for i := len(segments) - 1; i > 0; i-- {
+		if origin, ok := p.get(joinSegments(segments[:i])); ok {
+			return origin, true
+		}
+	}
+	if _, ok = value.(map[string]any); ok {
+		// Values of the object can come from different sources.
+		return nil, false
+	}
+	return &Origin{
+		Key:    configKey,
+		Source: OriginApplication,
+	}, true
+}
+
+// getOriginOptions returns the error options with the key and the origin of the value if it is known.
+func (m *manager) getOriginOptions(configKey string) []xerrors.Option {
+	options := []xerrors.Option{
+		xerrors.NewOption("componego:config:key", configKey),
+	}
+	if origin, ok := m.getOrigin(configKey); ok {
+		options = append(options, xerrors.NewOption("componego:config:origin", origin))
+	}
+	return options
+}
+
+// collectLeafKeys adds the keys of all values except objects. Lists are values too.
+func collectLeafKeys(prefix string, value any, keys *[]string) {
+	values, ok := value.(map[string]any)
+	if !ok {
+		if prefix != "" {
+			*keys = append(*keys, prefix)
+		}
+		return
+	}
+	sortedKeys := make([]string, 0, len(values))
+	for key := range values {
+		sortedKeys = append(sortedKeys, key)
+	}
+	sort.Strings(sortedKeys)
+	for _, key := range sortedKeys {
+		collectLeafKeys(joinKey(prefix, EscapeKey(key)), values[key], keys)
+	}
+}
+
+func joinSegments(segments []keySegment) string {
+	names := make([]string, len(segments))
+	for i, segment := range segments {
+		if segment.wildcard {
+			names[i] = segment.name
+		} else {
+			names[i] = EscapeKey(segment.name)
+		}
+	}
+	return strings.Join(names, delimiter)
+}
@@ -0,0 +1,141 @@
+/*
+Copyright 2024-present Volodymyr Konstanchuk and contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package readers
+
+import (
+	"bytes"
+	"strings"
+)
+
+// Dotenv reads configuration in dotenv format.
+// Keys that contain dots are converted into nested maps. All values are strings.
+//
+// Supported syntax:
+//
+//	# comment
+//	KEY=value
+//	export KEY=value
+//	KEY="value with \"escapes\"\n" # comment
+//	KEY='literal value'
+func Dotenv(data []byte) (map[string]any, error) {
+	settings := make(map[string]any)
+	for offset := 0; offset < len(data); {
+		lineEnd := bytes.IndexByte(data[offset:], '\n')
+		if lineEnd < 0 {
+			lineEnd = len(data)
+		} else {
+			lineEnd += offset
+		}
+		lineStart := offset
+		offset = lineEnd + 1
+		line := strings.TrimRight(string(data[lineStart:lineEnd]), "\r")
+		trimmedLine := strings.TrimLeft(line, " \t")
+		if trimmedLine == "" || trimmedLine[0] == '#' {
+			continue
+		}
+		indent := len(line) - len(trimmedLine)
+		if rest, ok := strings.CutPrefix(trimmedLine, "export "); ok {
+			indent += len(trimmedLine) - len(rest)
+			trimmedLine = rest
+		}
+		separator := strings.IndexByte(trimmedLine, '=')
+		if separator < 0 {
+			return nil, newSyntaxError(data, lineStart+indent, "expected '=' after the key")
+		}
+		key := strings.TrimSpace(trimmedLine[:separator])
+		if key == "" || strings.ContainsAny(key, " \t\"'") {
+			return nil, newSyntaxError(data, lineStart+indent, "invalid key '%s'", key)
+		}
+		valueStart := lineStart + indent + separator + 1
+		value, valueEnd, err := readDotenvValue(data, valueStart, lineEnd)
+		if err != nil {
+			return nil, err
+		}
+		if valueEnd > lineEnd {
+			// Quoted values can contain line breaks.
+			offset = bytes.IndexByte(data[valueEnd:], '\n')
+			if offset < 0 {
+				offset = len(data)
+			} else {
+				offset += valueEnd + 1
+			}
+		}
+		if !setValue(settings, strings.Split(key, "."), value, true) {
+			return nil, newSyntaxError(data, lineStart+indent, "key '%s' conflicts with another key", key)
+		}
+	}
+	return settings, nil
+}
+
+// readDotenvValue returns the value and the offset after the value.
+func readDotenvValue(data []byte, start int, lineEnd int) (string, int, error) {
+	for start < lineEnd && (data[start] == ' ' || data[start] == '\t') {
+		start++
+	}
+	if start >= lineEnd || (data[start] != '"' && data[start] != '\'') {
+		value := string(data[start:lineEnd])
+		// An unquoted value ends before the comment.
+		if index := strings.Index(value, " #"); index >= 0 {
+			value = value[:index]
+		}
+		return strings.TrimSpace(value), lineEnd, nil
+	}
+	quote := data[start]
+	builder := strings.Builder{}
+	for i := start + 1; i < len(data); i++ {
+		c := data[i]
+		if c == quote {
+			if err := checkDotenvRest(data, i+1); err != nil {
+				return "", 0, err
+			}
+			return builder.String(), i + 1, nil
+		}
+		if c == '\\' && quote == '"' && i+1 < len(data) {
+			i++
+			switch data[i] {
+			case 'n':
+				builder.WriteByte('\n')
+			case 'r':
+				builder.WriteByte('\r')
+			case 't':
+				builder.WriteByte('\t')
+			case '"', '\\', '$':
+				builder.WriteByte(data[i])
+			default:
+				builder.WriteByte('\\')
+				builder.WriteByte(data[i])
+			}
+			continue
+		}
+		builder.WriteByte(c)
+	}
+	return "", 0, newSyntaxError(data, start, "unterminated quoted value")
+}
+
+// checkDotenvRest checks that only a comment can follow the quoted value.
+func checkDotenvRest(data []byte, start int) error {
+	for i := start; i < len(data) && data[i] != '\n'; i++ {
+		switch data[i] {
+		case ' ', '\t', '\r':
+			continue
+		case '#':
+			return nil
+		}
+		return newSyntaxError(data, i, "unexpected character after the quoted value")
+	}
+	return nil
+}
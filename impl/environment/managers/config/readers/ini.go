@@ -0,0 +1,127 @@
+/*
+Copyright 2024-present Volodymyr Konstanchuk and contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package readers
+
+import (
+	"bytes"
+	"strings"
+)
+
+// INI reads configuration in INI format.
+// Sections and keys that contain dots are converted into nested maps. All values are strings.
+//
+// Supported syntax:
+//
+//	; comment
+//	# comment
+//	key = value
+//	[section]
+//	key: value
+//	[section.nested]
+//	key = "quoted value"
+func INI(data []byte) (map[string]any, error) {
+	settings := make(map[string]any)
+	section := settings
+	sectionName := ""
+	for offset := 0; offset < len(data); {
+		lineEnd := bytes.IndexByte(data[offset:], '\n')
+		if lineEnd < 0 {
+			lineEnd = len(data)
+		} else {
+			lineEnd += offset
+		}
+		lineStart := offset
+		offset = lineEnd + 1
+		line := strings.TrimRight(string(data[lineStart:lineEnd]), "\r")
+		trimmedLine := strings.TrimSpace(line)
+		if trimmedLine == "" || trimmedLine[0] == ';' || trimmedLine[0] == '#' {
+			continue
+		}
+		indent := lineStart + len(line) - len(strings.TrimLeft(line, " \t"))
+		if trimmedLine[0] == '[' {
+			if trimmedLine[len(trimmedLine)-1] != ']' {
+				return nil, newSyntaxError(data, indent, "expected ']' at the end of the section")
+			}
+			sectionName = strings.TrimSpace(trimmedLine[1 : len(trimmedLine)-1])
+			if sectionName == "" {
+				return nil, newSyntaxError(data, indent, "empty section name")
+			}
+			path := splitKey(sectionName)
+			// Sections with the same name are merged.
+			if existingSection, ok := getMap(settings, path); ok {
+				section = existingSection
+				continue
+			}
+			section = make(map[string]any)
+			if !setValue(settings, path, section, false) {
+				return nil, newSyntaxError(data, indent, "section '%s' conflicts with another key", sectionName)
+			}
+			continue
+		}
+		separator := strings.IndexAny(trimmedLine, "=:")
+		if separator < 0 {
+			return nil, newSyntaxError(data, indent, "expected '=' or ':' after the key")
+		}
+		key := strings.TrimSpace(trimmedLine[:separator])
+		if key == "" {
+			return nil, newSyntaxError(data, indent, "empty key")
+		}
+		value := strings.TrimSpace(trimmedLine[separator+1:])
+		if value != "" && (value[0] == '"' || value[0] == '\'') {
+			end := strings.IndexByte(value[1:], value[0])
+			if end < 0 {
+				return nil, newSyntaxError(data, indent, "unterminated quoted value")
+			}
+			rest := strings.TrimSpace(value[end+2:])
+			if rest != "" && rest[0] != ';' && rest[0] != '#' {
+				return nil, newSyntaxError(data, indent, "unexpected data after the quoted value")
+			}
+			value = value[1 : end+1]
+		} else if index := strings.IndexAny(value, ";#"); index > 0 && (value[index-1] == ' ' || value[index-1] == '\t') {
+			// Inline comments must be separated from the value by a space.
+			value = strings.TrimSpace(value[:index])
+		}
+		if !setValue(section, splitKey(key), value, false) {
+			fullKey := key
+			if sectionName != "" {
+				fullKey = sectionName + "." + key
+			}
+			return nil, newSyntaxError(data, indent, "duplicate key '%s'", fullKey)
+		}
+	}
+	return settings, nil
+}
+
+func splitKey(key string) []string {
+	path := strings.Split(key, ".")
+	for i := range path {
+		path[i] = strings.TrimSpace(path[i])
+	}
+	return path
+}
+
+// getMap returns the nested map by the path.
+func getMap(settings map[string]any, path []string) (map[string]any, bool) {
+	for _, key := range path {
+		nested, ok := settings[key].(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		settings = nested
+	}
+	return settings, true
+}
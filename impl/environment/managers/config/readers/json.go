@@ -0,0 +1,99 @@
+/*
+Copyright 2024-present Volodymyr Konstanchuk and contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package readers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+)
+
+// JSON reads configuration in JSON format.
+// Comments (// and /* */) and trailing commas are allowed.
+func JSON(data []byte) (map[string]any, error) {
+	cleanData, err := stripJSONComments(data)
+	if err != nil {
+		return nil, err
+	}
+	var settings map[string]any
+	decoder := json.NewDecoder(bytes.NewReader(cleanData))
+	if err = decoder.Decode(&settings); err != nil {
+		var (
+			syntaxErr *json.SyntaxError
+			typeErr   *json.UnmarshalTypeError
+		)
+		if errors.As(err, &syntaxErr) {
+			return nil, newSyntaxError(data, int(syntaxErr.Offset)-1, "%s", syntaxErr.Error())
+		} else if errors.As(err, &typeErr) {
+			return nil, newSyntaxError(data, int(typeErr.Offset)-1, "the root value must be an object")
+		}
+		// This happens when the data is empty or incomplete.
+		return nil, newSyntaxError(data, len(data), "%s", err.Error())
+	}
+	if decoder.More() {
+		return nil, newSyntaxError(data, int(decoder.InputOffset()), "unexpected data after the root object")
+	}
+	return settings, nil
+}
+
+// stripJSONComments replaces comments and trailing commas with spaces.
+// The data keeps its length and line breaks, so the positions of errors remain correct.
+func stripJSONComments(data []byte) ([]byte, error) {
+	result := make([]byte, len(data))
+	copy(result, data)
+	lastComma := -1
+	for i := 0; i < len(result); i++ {
+		switch c := result[i]; {
+		case c == '"':
+			// Strings are skipped as is.
+			for i++; i < len(result) && result[i] != '"'; i++ {
+				if result[i] == '\\' {
+					i++
+				}
+			}
+			lastComma = -1
+		case c == '/' && i+1 < len(result) && result[i+1] == '/':
+			for ; i < len(result) && result[i] != '\n'; i++ {
+				result[i] = ' '
+			}
+		case c == '/' && i+1 < len(result) && result[i+1] == '*':
+			start := i
+			end := bytes.Index(result[i+2:], []byte("*/"))
+			if end < 0 {
+				return nil, newSyntaxError(data, start, "unterminated comment")
+			}
+			for end = i + 2 + end + 2; i < end; i++ {
+				if result[i] != '\n' {
+					result[i] = ' '
+				}
+			}
+			i--
+		case c == ',':
+			lastComma = i
+		case c == '}' || c == ']':
+			if lastComma >= 0 {
+				result[lastComma] = ' '
+			}
+			lastComma = -1
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			// Whitespace does not affect trailing commas.
+		default:
+			lastComma = -1
+		}
+	}
+	return result, nil
+}
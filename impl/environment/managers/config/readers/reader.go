@@ -0,0 +1,118 @@
+/*
+Copyright 2024-present Volodymyr Konstanchuk and contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package readers
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/componego/componego/libs/xerrors"
+)
+
+var (
+	ErrReader = xerrors.New("config reader error", "E0330")
+	ErrSyntax = ErrReader.WithMessage("config syntax error", "E0331")
+)
+
+// ByExtension returns the reader for the file extension.
+// Files without an extension or with an unknown extension are read as JSON.
+// The result can be passed to config.NewFileSource.
+func ByExtension(filename string) func(data []byte) (map[string]any, error) {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".env":
+		return Dotenv
+	case ".ini":
+		return INI
+	case ".toml":
+		return TOML
+	}
+	if strings.HasPrefix(filepath.Base(filename), ".env") {
+		// For example, .env.local or .env.production.
+		return Dotenv
+	}
+	return JSON
+}
+
+// GetPosition returns the line and the column of the syntax error. The numbering starts from 1.
+func GetPosition(err error) (line int, column int, ok bool) {
+	for _, err := range xerrors.UnwrapAll(err) {
+		// noinspection ALL
+		xErr, ok := err.(xerrors.XError) //nolint:errorlint
+		if !ok || xErr.ErrorCode() != "E0332" {
+			continue
+		}
+		for _, option := range xErr.ErrorOptions() {
+			switch option.Key() {
+			case "componego:config:readers:line":
+				line, _ = option.Value().(int)
+			case "componego:config:readers:column":
+				column, _ = option.Value().(int)
+			}
+		}
+		return line, column, true
+	}
+	return 0, 0, false
+}
+
+// newSyntaxError returns an error with the position of the byte offset in the data.
+func newSyntaxError(data []byte, offset int, format string, args ...any) error {
+	line, column := getPosition(data, offset)
+	return ErrSyntax.WithMessage(fmt.Sprintf("%d:%d: %s", line, column, fmt.Sprintf(format, args...)), "E0332",
+		xerrors.NewOption("componego:config:readers:line", line),
+		xerrors.NewOption("componego:config:readers:column", column),
+	)
+}
+
+func getPosition(data []byte, offset int) (line int, column int) {
+	if offset > len(data) {
+		offset = len(data)
+	}
+	line, lineStart := 1, 0
+	for i := 0; i < offset; i++ {
+		if data[i] == '\n' {
+			line++
+			lineStart = i + 1
+		}
+	}
+	// Columns are counted in characters, not in bytes.
+	return line, utf8.RuneCount(data[lineStart:offset]) + 1
+}
+
+// setValue sets the value by the path and creates nested maps if necessary.
+// It returns false if the path conflicts with an existing value.
+func setValue(settings map[string]any, path []string, value any, replace bool) bool {
+	for _, key := range path[:len(path)-1] {
+		switch nested := settings[key].(type) {
+		case map[string]any:
+			settings = nested
+		case nil:
+			nestedSettings := make(map[string]any)
+			settings[key] = nestedSettings
+			settings = nestedSettings
+		default:
+			return false
+		}
+	}
+	key := path[len(path)-1]
+	if _, ok := settings[key]; ok && !replace {
+		return false
+	}
+	settings[key] = value
+	return true
+}
@@ -0,0 +1,314 @@
+/*
+Copyright 2024-present Volodymyr Konstanchuk and contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tests
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/componego/componego/impl/environment/managers/config/readers"
+	"github.com/componego/componego/internal/testing/require"
+)
+
+func requirePosition(t *testing.T, err error, expectedLine int, expectedColumn int) {
+	t.Helper()
+	require.ErrorIs(t, err, readers.ErrSyntax)
+	line, column, ok := readers.GetPosition(err)
+	require.True(t, ok)
+	require.Equal(t, expectedLine, line, err.Error())
+	require.Equal(t, expectedColumn, column, err.Error())
+}
+
+func TestJSON(t *testing.T) {
+	t.Run("comments and trailing commas", func(t *testing.T) {
+		settings, err := readers.JSON([]byte(`{
+			// comment
+			"server": {
+				"addr": ":8080", /* comment */
+				"url": "http://localhost//path",
+			},
+			"list": [1, 2,],
+		}`))
+		require.NoError(t, err)
+		require.Equal(t, map[string]any{
+			"server": map[string]any{
+				"addr": ":8080",
+				"url":  "http://localhost//path",
+			},
+			"list": []any{float64(1), float64(2)},
+		}, settings)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		_, err := readers.JSON([]byte("{\n  \"key\": value\n}"))
+		requirePosition(t, err, 2, 10)
+		_, err = readers.JSON([]byte("{\n  /* comment\n}"))
+		requirePosition(t, err, 2, 3)
+		_, err = readers.JSON([]byte("[1, 2]"))
+		requirePosition(t, err, 1, 1)
+		_, err = readers.JSON([]byte("{} {}"))
+		require.ErrorIs(t, err, readers.ErrSyntax)
+	})
+}
+
+func TestDotenv(t *testing.T) {
+	t.Run("values", func(t *testing.T) {
+		settings, err := readers.Dotenv([]byte(`
+# comment
+APP_NAME=test app # comment
+export APP_PORT = 8080
+APP_QUOTED="line 1\nline \"2\"" # comment
+APP_LITERAL='value # not a comment'
+APP_MULTILINE="line 1
+line 2"
+server.addr=:8080
+EMPTY=
+`))
+		require.NoError(t, err)
+		require.Equal(t, map[string]any{
+			"APP_NAME":      "test app",
+			"APP_PORT":      "8080",
+			"APP_QUOTED":    "line 1\nline \"2\"",
+			"APP_LITERAL":   "value # not a comment",
+			"APP_MULTILINE": "line 1\nline 2",
+			"server": map[string]any{
+				"addr": ":8080",
+			},
+			"EMPTY": "",
+		}, settings)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		_, err := readers.Dotenv([]byte("KEY=value\n  INVALID"))
+		requirePosition(t, err, 2, 3)
+		_, err = readers.Dotenv([]byte("KEY=\"value"))
+		requirePosition(t, err, 1, 5)
+		_, err = readers.Dotenv([]byte("KEY=\"value\" rest"))
+		requirePosition(t, err, 1, 13)
+	})
+}
+
+func TestINI(t *testing.T) {
+	t.Run("values", func(t *testing.T) {
+		settings, err := readers.INI([]byte(`
+; comment
+name = app
+[server]
+addr = :8080 ; comment
+host: "localhost" # comment
+[server.tls]
+enabled = true
+[database]
+url = postgres://localhost/db#fragment
+[server]
+timeout = 5s
+`))
+		require.NoError(t, err)
+		require.Equal(t, map[string]any{
+			"name": "app",
+			"server": map[string]any{
+				"addr":    ":8080",
+				"host":    "localhost",
+				"timeout": "5s",
+				"tls": map[string]any{
+					"enabled": "true",
+				},
+			},
+			"database": map[string]any{
+				"url": "postgres://localhost/db#fragment",
+			},
+		}, settings)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		_, err := readers.INI([]byte("[server\naddr = :8080"))
+		requirePosition(t, err, 1, 1)
+		_, err = readers.INI([]byte("[server]\naddr = 1\n  addr = 2"))
+		requirePosition(t, err, 3, 3)
+		_, err = readers.INI([]byte("key = value\n[key]"))
+		requirePosition(t, err, 2, 1)
+		_, err = readers.INI([]byte("invalid"))
+		requirePosition(t, err, 1, 1)
+	})
+}
+
+func TestTOML(t *testing.T) {
+	t.Run("values", func(t *testing.T) {
+		settings, err := readers.TOML([]byte(`
+# comment
+title = "TOML \"example\"" # comment
+literal = 'C:\path'
+"quoted key" = 1
+site."google.com" = true
+
+[owner]
+name = "Tom"
+dob = 1979-05-27T07:32:00-08:00
+local = 1979-05-27 07:32:00
+date = 1979-05-27
+time = 07:32:00
+
+[numbers]
+int = +99
+hex = 0xDEAD_BEEF
+oct = 0o755
+bin = 0b1101
+big = 1_000_000
+float = -3.14
+exp = 5e+22
+inf = inf
+nan = nan
+
+[strings]
+multiline = """
+Roses are red
+Violets are blue"""
+trimmed = """\
+    The quick brown \
+    fox."""
+raw = '''
+Line 1
+Line 2'''
+unicode = "\u00e9\U0001F600"
+
+[arrays]
+empty = []
+mixed = [
+  1,
+  "two", # comment
+  [3.0, 4.0],
+]
+inline = { x = 1, y.z = 2 }
+
+[[products]]
+name = "Hammer"
+
+[[products]]
+name = "Nail"
+[products.details]
+size = 2
+
+[a.b]
+c = 1
+[a]
+d = 2
+`))
+		require.NoError(t, err)
+		dob, _ := time.Parse(time.RFC3339, "1979-05-27T07:32:00-08:00")
+		local, _ := time.Parse(time.DateTime, "1979-05-27 07:32:00")
+		date, _ := time.Parse(time.DateOnly, "1979-05-27")
+		numbers := settings["numbers"].(map[string]any)
+		require.True(t, math.IsNaN(numbers["nan"].(float64)))
+		delete(numbers, "nan")
+		require.Equal(t, map[string]any{
+			"title":      `TOML "example"`,
+			"literal":    `C:\path`,
+			"quoted key": int64(1),
+			"site": map[string]any{
+				"google.com": true,
+			},
+			"owner": map[string]any{
+				"name":  "Tom",
+				"dob":   dob,
+				"local": local,
+				"date":  date,
+				"time":  "07:32:00",
+			},
+			"numbers": map[string]any{
+				"int":   int64(99),
+				"hex":   int64(0xDEADBEEF),
+				"oct":   int64(0o755),
+				"bin":   int64(13),
+				"big":   int64(1000000),
+				"float": -3.14,
+				"exp":   5e+22,
+				"inf":   math.Inf(1),
+			},
+			"strings": map[string]any{
+				"multiline": "Roses are red\nViolets are blue",
+				"trimmed":   "The quick brown fox.",
+				"raw":       "Line 1\nLine 2",
+				"unicode":   "\u00e9\U0001F600",
+			},
+			"arrays": map[string]any{
+				"empty": []any{},
+				"mixed": []any{int64(1), "two", []any{3.0, 4.0}},
+				"inline": map[string]any{
+					"x": int64(1),
+					"y": map[string]any{"z": int64(2)},
+				},
+			},
+			"products": []any{
+				map[string]any{"name": "Hammer"},
+				map[string]any{
+					"name": "Nail",
+					"details": map[string]any{
+						"size": int64(2),
+					},
+				},
+			},
+			"a": map[string]any{
+				"b": map[string]any{"c": int64(1)},
+				"d": int64(2),
+			},
+		}, settings)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		testCases := []struct {
+			data   string
+			line   int
+			column int
+		}{
+			{"key = 1\nkey = 2", 2, 1},
+			{"[table]\n[table]", 2, 1},
+			{"a.b = 1\n[a]", 2, 1},
+			{"inline = { x = 1 }\n[inline.y]", 2, 1},
+			{"key = \"unterminated\nnext = 1", 1, 7},
+			{"key = 007", 1, 7},
+			{"key = value", 1, 7},
+			{"key = 1 2", 1, 9},
+			{"[table\nkey = 1", 1, 7},
+			{"key = [1, 2", 1, 12},
+			{"key = \"\\q\"", 1, 8},
+			{"ключ = 1", 1, 1},
+			{"a = \"é\" b", 1, 9},
+			{"array = [1]\n[[array]]", 2, 1},
+		}
+		for _, testCase := range testCases {
+			_, err := readers.TOML([]byte(testCase.data))
+			requirePosition(t, err, testCase.line, testCase.column)
+		}
+	})
+}
+
+func TestByExtension(t *testing.T) {
+	settings, err := readers.ByExtension("config.toml")([]byte("key = 1"))
+	require.NoError(t, err)
+	require.Equal(t, map[string]any{"key": int64(1)}, settings)
+	settings, err = readers.ByExtension("./config/.env.local")([]byte("KEY=1"))
+	require.NoError(t, err)
+	require.Equal(t, map[string]any{"KEY": "1"}, settings)
+	settings, err = readers.ByExtension("config.INI")([]byte("key=1"))
+	require.NoError(t, err)
+	require.Equal(t, map[string]any{"key": "1"}, settings)
+	settings, err = readers.ByExtension("config.jsonc")([]byte(`{"key": 1, // comment
+	}`))
+	require.NoError(t, err)
+	require.Equal(t, map[string]any{"key": float64(1)}, settings)
+}
@@ -0,0 +1,653 @@
+/*
+Copyright 2024-present Volodymyr Konstanchuk and contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package readers
+
+import (
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// TOML reads configuration in TOML format.
+//
+// The reader supports a subset of TOML v1.0.0:
+//   - comments, bare, quoted and dotted keys;
+//   - tables, inline tables and arrays of tables;
+//   - basic, literal and multiline strings;
+//   - integers (int64) including hexadecimal, octal and binary forms, floats (float64) and booleans;
+//   - arrays;
+//   - offset date-times, local date-times and local dates (time.Time). Local times are returned as strings.
+func TOML(data []byte) (map[string]any, error) {
+	p := &tomlParser{
+		data:  data,
+		root:  make(map[string]any),
+		kinds: make(map[uintptr]tableKind),
+	}
+	p.current = p.root
+	p.kinds[getMapID(p.root)] = tableDefined
+	if err := p.parse(); err != nil {
+		return nil, err
+	}
+	return p.root, nil
+}
+
+type tableKind int
+
+const (
+	// tableImplicit is a table created by a header of its sub-table. It can be defined later.
+	tableImplicit tableKind = iota + 1
+	// tableDefined is a table created by a header or by a dotted key. It cannot be defined again.
+	tableDefined
+	// tableInline is an inline table. It cannot be changed.
+	tableInline
+)
+
+type tomlParser struct {
+	data    []byte
+	pos     int
+	root    map[string]any
+	current map[string]any
+	// Maps are not comparable, so the kinds of tables are stored by the map identity.
+	kinds map[uintptr]tableKind
+}
+
+func (p *tomlParser) parse() error {
+	for {
+		p.skipWhitespace()
+		if p.pos >= len(p.data) {
+			return nil
+		}
+		switch p.data[p.pos] {
+		case '\n':
+			p.pos++
+			continue
+		case '\r':
+			if err := p.expectNewLine(); err != nil {
+				return err
+			}
+			continue
+		case '#':
+			p.skipComment()
+			continue
+		case '[':
+			if err := p.parseTableHeader(); err != nil {
+				return err
+			}
+		default:
+			if err := p.parseKeyValue(p.current); err != nil {
+				return err
+			}
+		}
+		p.skipWhitespace()
+		if p.pos < len(p.data) && p.data[p.pos] == '#' {
+			p.skipComment()
+		}
+		if err := p.expectNewLine(); err != nil {
+			return err
+		}
+	}
+}
+
+func (p *tomlParser) parseTableHeader() error {
+	start := p.pos
+	isArray := p.hasPrefix("[[")
+	if isArray {
+		p.pos += 2
+	} else {
+		p.pos++
+	}
+	p.skipWhitespace()
+	path, err := p.parseKey()
+	if err != nil {
+		return err
+	}
+	p.skipWhitespace()
+	closing := "]"
+	if isArray {
+		closing = "]]"
+	}
+	if !p.hasPrefix(closing) {
+		return p.errorf("expected '%s' at the end of the table header", closing)
+	}
+	p.pos += len(closing)
+	parent, err := p.getTable(p.root, path[:len(path)-1], tableImplicit, start)
+	if err != nil {
+		return err
+	}
+	key := path[len(path)-1]
+	table := make(map[string]any)
+	switch value := parent[key].(type) {
+	case nil:
+		if isArray {
+			parent[key] = []any{table}
+		} else {
+			parent[key] = table
+		}
+	case []any:
+		if !isArray || !p.isArrayOfTables(value) {
+			return p.errorAt(start, "key '%s' is already defined", strings.Join(path, "."))
+		}
+		parent[key] = append(value, table)
+	case map[string]any:
+		// A table that was created implicitly can be defined once.
+		if isArray || p.kinds[getMapID(value)] != tableImplicit {
+			return p.errorAt(start, "table '%s' is already defined", strings.Join(path, "."))
+		}
+		table = value
+	default:
+		return p.errorAt(start, "key '%s' is already defined", strings.Join(path, "."))
+	}
+	p.kinds[getMapID(table)] = tableDefined
+	p.current = table
+	return nil
+}
+
+// getTable returns the table by the path and creates implicit tables if necessary.
+// The last element of an array of tables is used if the path points to the array.
+func (p *tomlParser) getTable(table map[string]any, path []string, kind tableKind, start int) (map[string]any, error) {
+	for i, key := range path {
+		switch value := table[key].(type) {
+		case nil:
+			nested := make(map[string]any)
+			table[key] = nested
+			p.kinds[getMapID(nested)] = kind
+			table = nested
+		case map[string]any:
+			if p.kinds[getMapID(value)] == tableInline {
+				return nil, p.errorAt(start, "inline table '%s' cannot be extended", strings.Join(path[:i+1], "."))
+			}
+			table = value
+		case []any:
+			if !p.isArrayOfTables(value) {
+				return nil, p.errorAt(start, "key '%s' is not a table", strings.Join(path[:i+1], "."))
+			}
+			table = value[len(value)-1].(map[string]any)
+		default:
+			return nil, p.errorAt(start, "key '%s' is not a table", strings.Join(path[:i+1], "."))
+		}
+	}
+	return table, nil
+}
+
+func (p *tomlParser) parseKeyValue(table map[string]any) error {
+	start := p.pos
+	path, err := p.parseKey()
+	if err != nil {
+		return err
+	}
+	p.skipWhitespace()
+	if p.pos >= len(p.data) || p.data[p.pos] != '=' {
+		return p.errorf("expected '=' after the key")
+	}
+	p.pos++
+	p.skipWhitespace()
+	value, err := p.parseValue()
+	if err != nil {
+		return err
+	}
+	// Dotted keys create tables that cannot be defined later by a header.
+	parent, err := p.getTable(table, path[:len(path)-1], tableDefined, start)
+	if err != nil {
+		return err
+	}
+	key := path[len(path)-1]
+	if _, ok := parent[key]; ok {
+		return p.errorAt(start, "key '%s' is already defined", strings.Join(path, "."))
+	}
+	parent[key] = value
+	return nil
+}
+
+func (p *tomlParser) parseKey() ([]string, error) {
+	path := make([]string, 0, 1)
+	for {
+		p.skipWhitespace()
+		if p.pos >= len(p.data) {
+			return nil, p.errorf("expected a key")
+		}
+		var (
+			key string
+			err error
+		)
+		switch p.data[p.pos] {
+		case '"':
+			key, err = p.parseBasicString()
+		case '\'':
+			key, err = p.parseLiteralString()
+		default:
+			start := p.pos
+			for p.pos < len(p.data) && isBareKeyChar(p.data[p.pos]) {
+				p.pos++
+			}
+			if start == p.pos {
+				return nil, p.errorf("invalid character in the key")
+			}
+			key = string(p.data[start:p.pos])
+		}
+		if err != nil {
+			return nil, err
+		}
+		path = append(path, key)
+		p.skipWhitespace()
+		if p.pos >= len(p.data) || p.data[p.pos] != '.' {
+			return path, nil
+		}
+		p.pos++
+	}
+}
+
+func (p *tomlParser) parseValue() (any, error) {
+	if p.pos >= len(p.data) {
+		return nil, p.errorf("expected a value")
+	}
+	switch c := p.data[p.pos]; {
+	case c == '"':
+		if p.hasPrefix(`"""`) {
+			return p.parseMultilineString(`"""`)
+		}
+		return p.parseBasicString()
+	case c == '\'':
+		if p.hasPrefix("'''") {
+			return p.parseMultilineString("'''")
+		}
+		return p.parseLiteralString()
+	case c == '[':
+		return p.parseArray()
+	case c == '{':
+		return p.parseInlineTable()
+	case p.hasPrefix("true") && p.isValueEnd(p.pos+4):
+		p.pos += 4
+		return true, nil
+	case p.hasPrefix("false") && p.isValueEnd(p.pos+5):
+		p.pos += 5
+		return false, nil
+	}
+	return p.parseScalar()
+}
+
+func (p *tomlParser) parseArray() (any, error) {
+	p.pos++ // [
+	result := make([]any, 0)
+	for {
+		p.skipWhitespaceAndComments()
+		if p.pos >= len(p.data) {
+			return nil, p.errorf("unterminated array")
+		}
+		if p.data[p.pos] == ']' {
+			p.pos++
+			return result, nil
+		}
+		value, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, value)
+		p.skipWhitespaceAndComments()
+		if p.pos < len(p.data) && p.data[p.pos] == ',' {
+			p.pos++
+			continue
+		}
+		if p.pos >= len(p.data) || p.data[p.pos] != ']' {
+			return nil, p.errorf("expected ',' or ']' in the array")
+		}
+	}
+}
+
+func (p *tomlParser) parseInlineTable() (any, error) {
+	p.pos++ // {
+	table := make(map[string]any)
+	p.skipWhitespace()
+	if p.pos < len(p.data) && p.data[p.pos] == '}' {
+		p.pos++
+		p.markInline(table)
+		return table, nil
+	}
+	for {
+		p.skipWhitespace()
+		if err := p.parseKeyValue(table); err != nil {
+			return nil, err
+		}
+		p.skipWhitespace()
+		if p.pos >= len(p.data) {
+			return nil, p.errorf("unterminated inline table")
+		}
+		switch p.data[p.pos] {
+		case ',':
+			p.pos++
+		case '}':
+			p.pos++
+			p.markInline(table)
+			return table, nil
+		default:
+			return nil, p.errorf("expected ',' or '}' in the inline table")
+		}
+	}
+}
+
+func (p *tomlParser) parseBasicString() (string, error) {
+	start := p.pos
+	p.pos++ // "
+	builder := strings.Builder{}
+	for p.pos < len(p.data) {
+		c := p.data[p.pos]
+		switch c {
+		case '"':
+			p.pos++
+			return builder.String(), nil
+		case '\n', '\r':
+			return "", p.errorAt(start, "unterminated string")
+		case '\\':
+			if err := p.parseEscape(&builder); err != nil {
+				return "", err
+			}
+			continue
+		}
+		builder.WriteByte(c)
+		p.pos++
+	}
+	return "", p.errorAt(start, "unterminated string")
+}
+
+func (p *tomlParser) parseLiteralString() (string, error) {
+	start := p.pos
+	p.pos++ // '
+	for i := p.pos; i < len(p.data); i++ {
+		switch p.data[i] {
+		case '\'':
+			value := string(p.data[p.pos:i])
+			p.pos = i + 1
+			return value, nil
+		case '\n', '\r':
+			return "", p.errorAt(start, "unterminated string")
+		}
+	}
+	return "", p.errorAt(start, "unterminated string")
+}
+
+func (p *tomlParser) parseMultilineString(delimiter string) (string, error) {
+	start := p.pos
+	p.pos += len(delimiter)
+	// A line break immediately after the opening delimiter is trimmed.
+	if p.hasPrefix("\r\n") {
+		p.pos += 2
+	} else if p.hasPrefix("\n") {
+		p.pos++
+	}
+	builder := strings.Builder{}
+	for p.pos < len(p.data) {
+		if p.hasPrefix(delimiter) {
+			p.pos += len(delimiter)
+			// Up to two quotes can be placed right before the closing delimiter.
+			for i := 0; i < 2 && p.pos < len(p.data) && p.data[p.pos] == delimiter[0]; i++ {
+				builder.WriteByte(delimiter[0])
+				p.pos++
+			}
+			return builder.String(), nil
+		}
+		c := p.data[p.pos]
+		if c == '\\' && delimiter == `"""` {
+			// A line ending backslash trims all whitespace up to the next non-whitespace character.
+			next := p.pos + 1
+			for next < len(p.data) && (p.data[next] == ' ' || p.data[next] == '\t') {
+				next++
+			}
+			if next < len(p.data) && (p.data[next] == '\n' || p.data[next] == '\r') {
+				for next < len(p.data) && strings.IndexByte(" \t\r\n", p.data[next]) >= 0 {
+					next++
+				}
+				p.pos = next
+				continue
+			}
+			if err := p.parseEscape(&builder); err != nil {
+				return "", err
+			}
+			continue
+		}
+		builder.WriteByte(c)
+		p.pos++
+	}
+	return "", p.errorAt(start, "unterminated string")
+}
+
+func (p *tomlParser) parseEscape(builder *strings.Builder) error {
+	start := p.pos
+	p.pos++ // \
+	if p.pos >= len(p.data) {
+		return p.errorAt(start, "invalid escape sequence")
+	}
+	c := p.data[p.pos]
+	p.pos++
+	switch c {
+	case 'b':
+		builder.WriteByte('\b')
+	case 't':
+		builder.WriteByte('\t')
+	case 'n':
+		builder.WriteByte('\n')
+	case 'f':
+		builder.WriteByte('\f')
+	case 'r':
+		builder.WriteByte('\r')
+	case '"', '\\':
+		builder.WriteByte(c)
+	case 'u', 'U':
+		size := 4
+		if c == 'U' {
+			size = 8
+		}
+		if p.pos+size > len(p.data) {
+			return p.errorAt(start, "invalid unicode escape sequence")
+		}
+		code, err := strconv.ParseUint(string(p.data[p.pos:p.pos+size]), 16, 32)
+		if err != nil || !utf8.ValidRune(rune(code)) {
+			return p.errorAt(start, "invalid unicode escape sequence")
+		}
+		builder.WriteRune(rune(code))
+		p.pos += size
+	default:
+		return p.errorAt(start, "invalid escape sequence")
+	}
+	return nil
+}
+
+// parseScalar parses numbers and dates.
+func (p *tomlParser) parseScalar() (any, error) {
+	start := p.pos
+	for p.pos < len(p.data) && !p.isValueEnd(p.pos) {
+		p.pos++
+	}
+	token := string(p.data[start:p.pos])
+	// A date and a time can be separated by a space.
+	if isDate(token) && p.pos+3 < len(p.data) && p.data[p.pos] == ' ' &&
+		isDigit(p.data[p.pos+1]) && isDigit(p.data[p.pos+2]) && p.data[p.pos+3] == ':' {
+		p.pos++
+		for p.pos < len(p.data) && !p.isValueEnd(p.pos) {
+			p.pos++
+		}
+		token = string(p.data[start:p.pos])
+	}
+	if token == "" {
+		return nil, p.errorf("expected a value")
+	}
+	if value, ok := parseTOMLDate(token); ok {
+		return value, nil
+	}
+	if value, ok := parseTOMLNumber(token); ok {
+		return value, nil
+	}
+	return nil, p.errorAt(start, "invalid value '%s'", token)
+}
+
+func parseTOMLDate(token string) (any, bool) {
+	layouts := []string{
+		time.RFC3339Nano,
+		"2006-01-02 15:04:05.999999999Z07:00",
+		"2006-01-02T15:04:05.999999999",
+		"2006-01-02 15:04:05.999999999",
+		"2006-01-02",
+	}
+	for _, layout := range layouts {
+		if value, err := time.Parse(layout, token); err == nil {
+			return value, true
+		}
+	}
+	// Local times do not have an equivalent in Go.
+	if _, err := time.Parse("15:04:05.999999999", token); err == nil {
+		return token, true
+	}
+	return nil, false
+}
+
+func parseTOMLNumber(token string) (any, bool) {
+	switch token {
+	case "inf", "+inf":
+		return math.Inf(1), true
+	case "-inf":
+		return math.Inf(-1), true
+	case "nan", "+nan", "-nan":
+		return math.NaN(), true
+	}
+	if strings.HasPrefix(token, "_") || strings.HasSuffix(token, "_") || strings.Contains(token, "__") {
+		return nil, false
+	}
+	clean := strings.ReplaceAll(token, "_", "")
+	if len(clean) > 2 && clean[0] == '0' {
+		base := 0
+		switch clean[1] {
+		case 'x':
+			base = 16
+		case 'o':
+			base = 8
+		case 'b':
+			base = 2
+		}
+		if base != 0 {
+			value, err := strconv.ParseInt(clean[2:], base, 64)
+			return value, err == nil
+		}
+	}
+	if value, err := strconv.ParseInt(clean, 10, 64); err == nil {
+		digits := strings.TrimLeft(clean, "+-")
+		// Leading zeros are not allowed.
+		return value, len(digits) == 1 || digits[0] != '0'
+	}
+	if strings.ContainsAny(clean, "xob") || strings.HasPrefix(clean, ".") || strings.HasSuffix(clean, ".") {
+		return nil, false
+	}
+	value, err := strconv.ParseFloat(clean, 64)
+	return value, err == nil
+}
+
+// markInline marks the inline table and all tables inside it as unchangeable.
+func (p *tomlParser) markInline(table map[string]any) {
+	p.kinds[getMapID(table)] = tableInline
+	for _, value := range table {
+		if nested, ok := value.(map[string]any); ok {
+			p.markInline(nested)
+		}
+	}
+}
+
+// isArrayOfTables returns true if the array was created by headers of arrays of tables.
+func (p *tomlParser) isArrayOfTables(values []any) bool {
+	if len(values) == 0 {
+		return false
+	}
+	for _, value := range values {
+		table, ok := value.(map[string]any)
+		if !ok || p.kinds[getMapID(table)] != tableDefined {
+			return false
+		}
+	}
+	return true
+}
+
+func (p *tomlParser) skipWhitespace() {
+	for p.pos < len(p.data) && (p.data[p.pos] == ' ' || p.data[p.pos] == '\t') {
+		p.pos++
+	}
+}
+
+func (p *tomlParser) skipComment() {
+	for p.pos < len(p.data) && p.data[p.pos] != '\n' {
+		p.pos++
+	}
+}
+
+func (p *tomlParser) skipWhitespaceAndComments() {
+	for p.pos < len(p.data) {
+		switch p.data[p.pos] {
+		case ' ', '\t', '\r', '\n':
+			p.pos++
+		case '#':
+			p.skipComment()
+		default:
+			return
+		}
+	}
+}
+
+func (p *tomlParser) expectNewLine() error {
+	if p.pos >= len(p.data) {
+		return nil
+	}
+	if p.hasPrefix("\r\n") {
+		p.pos += 2
+		return nil
+	}
+	if p.data[p.pos] == '\n' {
+		p.pos++
+		return nil
+	}
+	return p.errorf("expected a new line")
+}
+
+func (p *tomlParser) hasPrefix(prefix string) bool {
+	return strings.HasPrefix(string(p.data[p.pos:min(p.pos+len(prefix), len(p.data))]), prefix)
+}
+
+func (p *tomlParser) isValueEnd(pos int) bool {
+	return pos >= len(p.data) || strings.IndexByte(" \t\r\n,]}#", p.data[pos]) >= 0
+}
+
+func (p *tomlParser) errorf(format string, args ...any) error {
+	return p.errorAt(p.pos, format, args...)
+}
+
+func (p *tomlParser) errorAt(pos int, format string, args ...any) error {
+	return newSyntaxError(p.data, pos, format, args...)
+}
+
+func getMapID(table map[string]any) uintptr {
+	return reflect.ValueOf(table).Pointer()
+}
+
+func isBareKeyChar(c byte) bool {
+	return c == '_' || c == '-' || isDigit(c) || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isDate(token string) bool {
+	_, err := time.Parse("2006-01-02", token)
+	return err == nil
+}
@@ -0,0 +1,337 @@
+/*
+Copyright 2024-present Volodymyr Konstanchuk and contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/componego/componego"
+	"github.com/componego/componego/internal/utils"
+	"github.com/componego/componego/libs/xerrors"
+)
+
+var (
+	ErrConfigReload        = ErrConfigManager.WithMessage("config reload error", "E0340")
+	ErrReloadNotSupported  = ErrConfigReload.WithMessage("config provider does not support reloading", "E0341")
+	ErrStaticConfigChanged = ErrConfigReload.WithMessage("static configuration cannot be changed", "E0342")
+)
+
+// Change contains the configuration keys that were changed during reloading.
+type Change struct {
+	// Prefix is the prefix of the subscription.
+	Prefix string
+	// Keys are sorted keys of the changed values with the prefix. Keys of nested values are joined with a dot.
+	Keys []string
+}
+
+type reloadState struct {
+	// reloadMutex guarantees that only one reload runs at the same time.
+	reloadMutex   sync.Mutex
+	mutex         sync.Mutex
+	loader        func() (*Settings, error)
+	processors    map[string][]componego.Processor
+	subscriptions []*subscription
+}
+
+type subscription struct {
+	prefix string
+	fn     func(change *Change)
+}
+
+func newReloadState() *reloadState {
+	return &reloadState{
+		processors: make(map[string][]componego.Processor),
+	}
+}
+
+// addProcessor remembers the processor of the key. Each distinct processor is stored once.
+// All processors are stored, so none of the checks is lost when the configuration is reloaded.
+func (r *reloadState) addProcessor(configKey string, processor componego.Processor) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	processors := r.processors[configKey]
+	for _, item := range processors {
+		if isSameProcessor(item, processor) {
+			return
+		}
+	}
+	r.processors[configKey] = append(processors, processor)
+}
+
+// ApplicationConfigReload is an interface that describes an application whose configuration can be reloaded.
+// The configuration is not reloaded if the application does not implement this interface.
+type ApplicationConfigReload interface {
+	// Application belongs to the application.
+	componego.Application
+	// ApplicationConfigReload returns the new configuration of the application.
+	// This function is called each time the configuration is reloaded.
+	ApplicationConfigReload(appMode componego.ApplicationMode, options any) (*Settings, error)
+}
+
+// EnableReload allows the configuration to be reloaded. The loader must return the new configuration of the application.
+func EnableReload(env componego.Environment, loader func() (*Settings, error)) error {
+	manager, err := getManager(env)
+	if err != nil {
+		return err
+	}
+	manager.reload.mutex.Lock()
+	defer manager.reload.mutex.Unlock()
+	manager.reload.loader = loader
+	return nil
+}
+
+// EnableApplicationReload allows the configuration to be reloaded if the application implements ApplicationConfigReload.
+// The driver calls this function after the configuration is initialized.
+func EnableApplicationReload(env componego.Environment, options any) error {
+	app, ok := env.Application().(ApplicationConfigReload)
+	if !ok {
+		return nil
+	}
+	return EnableReload(env, func() (*Settings, error) {
+		return app.ApplicationConfigReload(env.ApplicationMode(), options)
+	})
+}
+
+// Reload reads the configuration of the application again and replaces the current configuration.
+// The new configuration is checked against the schema of the components
+// and by all processors that were used to read configuration values before.
+// The current configuration does not change if any check fails.
+// Subscribers are notified after the configuration is replaced, so they can call Reload again.
+func Reload(env componego.Environment) error {
+	manager, err := getManager(env)
+	if err != nil {
+		return err
+	}
+	return manager.reloadConfig()
+}
+
+// Subscribe adds a function that is called after reloading if any value with the prefix changes.
+// An empty prefix means the whole configuration. The returned function removes the subscription.
+func Subscribe(env componego.Environment, prefix string, fn func(change *Change)) (func(), error) {
+	manager, err := getManager(env)
+	if err != nil {
+		return nil, err
+	}
+	s := &subscription{
+		prefix: prefix,
+		fn:     fn,
+	}
+	manager.reload.mutex.Lock()
+	defer manager.reload.mutex.Unlock()
+	manager.reload.subscriptions = append(manager.reload.subscriptions, s)
+	return func() {
+		manager.reload.mutex.Lock()
+		defer manager.reload.mutex.Unlock()
+		for i, item := range manager.reload.subscriptions {
+			if item == s {
+				manager.reload.subscriptions = append(manager.reload.subscriptions[:i:i], manager.reload.subscriptions[i+1:]...)
+				return
+			}
+		}
+	}, nil
+}
+
+func getManager(env componego.Environment) (*manager, error) {
+	manager, ok := env.ConfigProvider().(*manager)
+	if !ok {
+		return nil, ErrReloadNotSupported.WithOptions("E0343",
+			xerrors.NewOption("componego:config:provider", env.ConfigProvider()),
+		)
+	}
+	return manager, nil
+}
+
+func (m *manager) reloadConfig() error {
+	changedKeys, subscriptions, err := m.replaceConfig()
+	if err != nil || len(changedKeys) == 0 {
+		return err
+	}
+	// Subscribers are notified without the reload lock, so they can reload the configuration themselves.
+	errs := make([]error, 0)
+	for _, s := range subscriptions {
+		if change := s.getChange(changedKeys); change != nil {
+			if err = s.notify(change); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	if len(errs) > 0 {
+		return ErrConfigReload.WithError(errors.Join(errs...), "E0348")
+	}
+	return nil
+}
+
+// replaceConfig loads and checks the new configuration and replaces the current configuration.
+// It returns the changed keys and the subscriptions that must be notified about them.
+func (m *manager) replaceConfig() ([]string, []*subscription, error) {
+	m.reload.reloadMutex.Lock()
+	defer m.reload.reloadMutex.Unlock()
+	// The state is copied, so processors can read the configuration during reloading.
+	m.reload.mutex.Lock()
+	loader := m.reload.loader
+	processors := make(map[string][]componego.Processor, len(m.reload.processors))
+	for configKey, items := range m.reload.processors {
+		processors[configKey] = utils.Copy(items)
+	}
+	m.reload.mutex.Unlock()
+	if loader == nil {
+		return nil, nil, ErrReloadNotSupported.WithOptions("E0344")
+	}
+	settings, err := loader()
+	if err != nil {
+		return nil, nil, ErrConfigReload.WithError(err, "E0345")
+	} else if settings == nil {
+		settings = NewSettings(nil)
+	}
+	next := &manager{
+		env:          m.env,
+		parsedConfig: settings.Values,
+		provenance:   settings.getOrigins().copy(),
+	}
+	if err = applyComponentDefaults(m.env, next); err != nil {
+		return nil, nil, ErrConfigReload.WithError(err, "E0346")
+	}
+	err = validateSchema(m.env, next.extractValue, next.getOriginOptions)
+	if err != nil {
+		return nil, nil, err
+	}
+	m.mutex.RLock()
+	prevConfig := m.parsedConfig
+	m.mutex.RUnlock()
+	if err = checkStaticConfig(m.env, prevConfig, next.parsedConfig); err != nil {
+		return nil, nil, err
+	}
+	for configKey, items := range processors {
+		value, _ := extractValue(next.parsedConfig, configKey)
+		for _, processor := range items {
+			if _, err = processor.ProcessData(value); err != nil {
+				return nil, nil, ErrConfigReload.WithError(err, "E0347", next.getOriginOptions(configKey)...)
+			}
+		}
+	}
+	m.mutex.Lock()
+	m.parsedConfig = next.parsedConfig
+	m.provenance = next.provenance
+	m.mutex.Unlock()
+	m.reload.mutex.Lock()
+	subscriptions := m.reload.subscriptions
+	m.reload.mutex.Unlock()
+	return getChangedKeys(prevConfig, next.parsedConfig), subscriptions, nil
+}
+
+// isSameProcessor returns true if both variables contain the same processor.
+func isSameProcessor(processor1 componego.Processor, processor2 componego.Processor) bool {
+	// Processors of incomparable types cannot be compared without panic.
+	return reflect.TypeOf(processor1) == reflect.TypeOf(processor2) && reflect.TypeOf(processor1).Comparable() && processor1 == processor2
+}
+
+// checkStaticConfig returns an error if the values that components cannot reload are changed.
+func checkStaticConfig(env componego.Environment, prevConfig map[string]any, nextConfig map[string]any) error {
+	for _, component := range env.Components() {
+		component, ok := component.(componego.ComponentConfigStatic)
+		if !ok {
+			continue
+		}
+		for _, prefix := range component.ComponentConfigStatic() {
+			prevValue, nextValue := any(prevConfig), any(nextConfig)
+			if prefix != "" {
+				prevValue, _ = extractValue(prevConfig, prefix)
+				nextValue, _ = extractValue(nextConfig, prefix)
+			}
+			if !reflect.DeepEqual(prevValue, nextValue) {
+				return ErrStaticConfigChanged.WithOptions("E0349",
+					xerrors.NewOption("componego:config:component", component),
+					xerrors.NewOption("componego:config:key", prefix),
+				)
+			}
+		}
+	}
+	return nil
+}
+
+func (s *subscription) getChange(changedKeys []string) *Change {
+	keys := make([]string, 0)
+	for _, key := range changedKeys {
+		if s.prefix == "" || key == s.prefix || strings.HasPrefix(key, s.prefix+delimiter) || strings.HasPrefix(s.prefix, key+delimiter) {
+			keys = append(keys, key)
+		}
+	}
+	if len(keys) == 0 {
+		return nil
+	}
+	return &Change{
+		Prefix: s.prefix,
+		Keys:   keys,
+	}
+}
+
+// notify calls the subscriber. A panic inside the subscriber is converted into an error.
+func (s *subscription) notify(change *Change) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = ErrConfigReload.WithMessage(fmt.Sprintf("config subscriber panicked: %v", r), "E0350",
+				xerrors.NewOption("componego:config:key", s.prefix),
+			)
+		}
+	}()
+	s.fn(change)
+	return nil
+}
+
+// getChangedKeys returns sorted keys of all values that were added, removed or changed.
+func getChangedKeys(prevConfig map[string]any, nextConfig map[string]any) []string {
+	keys := make([]string, 0)
+	collectChangedKeys("", prevConfig, nextConfig, &keys)
+	sort.Strings(keys)
+	return keys
+}
+
+func collectChangedKeys(prefix string, prevConfig map[string]any, nextConfig map[string]any, keys *[]string) {
+	for key, prevValue := range prevConfig {
+		fullKey := EscapeKey(key)
+		if prefix != "" {
+			fullKey = prefix + delimiter + fullKey
+		}
+		nextValue, ok := nextConfig[key]
+		if !ok {
+			*keys = append(*keys, fullKey)
+			continue
+		}
+		prevMap, prevOk := prevValue.(map[string]any)
+		nextMap, nextOk := nextValue.(map[string]any)
+		if prevOk && nextOk {
+			collectChangedKeys(fullKey, prevMap, nextMap, keys)
+		} else if !reflect.DeepEqual(prevValue, nextValue) {
+			*keys = append(*keys, fullKey)
+		}
+	}
+	for key := range nextConfig {
+		if _, ok := prevConfig[key]; ok {
+			continue
+		}
+		fullKey := EscapeKey(key)
+		if prefix != "" {
+			fullKey = prefix + delimiter + fullKey
+		}
+		*keys = append(*keys, fullKey)
+	}
+}
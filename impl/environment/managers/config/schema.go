@@ -0,0 +1,298 @@
+/*
+Copyright 2024-present Volodymyr Konstanchuk and contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/componego/componego"
+	"github.com/componego/componego/libs/type-cast"
+	"github.com/componego/componego/libs/xerrors"
+)
+
+var (
+	ErrConfigSchema  = ErrConfigManager.WithMessage("config schema error", "E0360")
+	ErrInvalidConfig = ErrConfigSchema.WithMessage("configuration does not match the schema", "E0361")
+)
+
+// Types of configuration values that can be used in componego.ConfigKey.
+const (
+	TypeString   = "string"
+	TypeInt      = "int"
+	TypeFloat    = "float"
+	TypeBool     = "bool"
+	TypeDuration = "duration"
+	TypeList     = "list"
+	TypeMap      = "map"
+	TypeAny      = "any"
+)
+
+// SchemaKey is a configuration key of the schema.
+type SchemaKey struct {
+	componego.ConfigKey
+	// Component is the component that registered the key.
+	Component componego.Component
+}
+
+// GetSchema returns the configuration keys of the components sorted by key.
+// The key of the result contains the namespace of the component.
+func GetSchema(components []componego.Component) ([]*SchemaKey, error) {
+	result := make([]*SchemaKey, 0)
+	registered := make(map[string]*SchemaKey)
+	for _, component := range components {
+		component, ok := component.(componego.ComponentConfigSchema)
+		if !ok {
+			continue
+		}
+		namespace, keys, err := component.ComponentConfigSchema()
+		if err != nil {
+			return nil, ErrConfigSchema.WithError(err, "E0362",
+				xerrors.NewOption("componego:config:component", component),
+			)
+		}
+		for _, key := range keys {
+			if key == nil {
+				continue
+			}
+			schemaKey := &SchemaKey{
+				ConfigKey: *key,
+				Component: component,
+			}
+			schemaKey.Key = joinKey(namespace, key.Key)
+			if !isKnownType(schemaKey.Type) {
+				return nil, ErrConfigSchema.WithMessage(fmt.Sprintf("unknown type %q of key %q", schemaKey.Type, schemaKey.Key), "E0363",
+					xerrors.NewOption("componego:config:component", component),
+					xerrors.NewOption("componego:config:key", schemaKey.Key),
+				)
+			}
+			if prevKey, ok := registered[schemaKey.Key]; ok {
+				return nil, ErrConfigSchema.WithMessage(fmt.Sprintf("key %q is registered more than once", schemaKey.Key), "E0364",
+					xerrors.NewOption("componego:config:component", component),
+					xerrors.NewOption("componego:config:component:prev", prevKey.Component),
+					xerrors.NewOption("componego:config:key", schemaKey.Key),
+				)
+			}
+			registered[schemaKey.Key] = schemaKey
+			result = append(result, schemaKey)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Key < result[j].Key
+	})
+	return result, nil
+}
+
+// ValidateSchema checks the configuration of the application against the schema of all active components.
+// All errors are returned at once. Each error contains the key of the invalid value.
+// The driver calls this function after the dependencies are created and when the configuration is reloaded.
+// Dependencies are injected into processors of the schema before they are called.
+func ValidateSchema(env componego.Environment) error {
+	configProvider := env.ConfigProvider()
+	lookup := func(configKey string) (any, bool) {
+		value, err := configProvider.ConfigValue(configKey, nil)
+		return value, err == nil
+	}
+	if manager, ok := configProvider.(*manager); ok {
+		return validateSchema(env, lookup, manager.getOriginOptions)
+	}
+	return validateSchema(env, lookup, func(configKey string) []xerrors.Option {
+		return []xerrors.Option{
+			xerrors.NewOption("componego:config:key", configKey),
+		}
+	})
+}
+
+// validateSchema checks the values returned by the lookup function.
+// The options of each error contain the key and, if it is known, the origin of the value.
+func validateSchema(
+	env componego.Environment,
+	lookup func(configKey string) (any, bool),
+	getOptions func(configKey string) []xerrors.Option,
+) error {
+	schema, err := GetSchema(env.Components())
+	if err != nil {
+		return err
+	}
+	errs := make([]error, 0)
+	for _, schemaKey := range schema {
+		value, ok := lookup(schemaKey.Key)
+		if err = schemaKey.validate(env, value, ok); err != nil {
+			options := append([]xerrors.Option{
+				xerrors.NewOption("componego:config:component", schemaKey.Component),
+			}, getOptions(schemaKey.Key)...)
+			errs = append(errs, ErrInvalidConfig.WithError(fmt.Errorf("%s: %w", schemaKey.Key, err), "E0365", options...))
+		}
+	}
+	if len(errs) > 0 {
+		return ErrInvalidConfig.WithError(errors.Join(errs...), "E0366")
+	}
+	return nil
+}
+
+func (s *SchemaKey) validate(env componego.Environment, value any, ok bool) error {
+	if !ok || value == nil {
+		if s.Required {
+			return errors.New("value is required")
+		}
+		return nil
+	}
+	if !isValidType(s.Type, value) {
+		return fmt.Errorf("expected %s, got %T", s.Type, value)
+	}
+	for _, processor := range s.Processors {
+		// Injecting a dependency into an object before calling the object's methods.
+		err := env.DependencyInvoker().PopulateFields(processor)
+		if err != nil {
+			return err
+		}
+		if value, err = processor.ProcessData(value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func isKnownType(valueType string) bool {
+	switch valueType {
+	case "", TypeString, TypeInt, TypeFloat, TypeBool, TypeDuration, TypeList, TypeMap, TypeAny:
+		return true
+	}
+	return false
+}
+
+// isValidType returns true if the value can be converted to the type.
+// Strings are accepted for scalar types because values of environment variables are always strings.
+func isValidType(valueType string, value any) bool {
+	var err error
+	switch valueType {
+	case TypeString:
+		_, err = type_cast.ToString(value)
+	case TypeInt:
+		_, err = type_cast.ToInt64(value)
+	case TypeFloat:
+		_, err = type_cast.ToFloat64(value)
+	case TypeBool:
+		_, err = type_cast.ToBool(value)
+	case TypeDuration:
+		switch castedValue := value.(type) {
+		case time.Duration:
+		case string:
+			_, err = time.ParseDuration(castedValue)
+		default:
+			return false
+		}
+	case TypeList:
+		if _, ok := value.(string); ok {
+			// A string is split by commas.
+			return true
+		}
+		kind := reflect.TypeOf(value).Kind()
+		return kind == reflect.Slice || kind == reflect.Array
+	case TypeMap:
+		_, ok := value.(map[string]any)
+		return ok
+	}
+	return err == nil
+}
+
+// MarkdownReference returns a Markdown table with all keys of the schema.
+func MarkdownReference(schema []*SchemaKey) string {
+	builder := &strings.Builder{}
+	builder.WriteString("| Key | Type | Default | Required | Component | Description |\n")
+	builder.WriteString("|-----|------|---------|----------|-----------|-------------|\n")
+	for _, schemaKey := range schema {
+		defaultValue := ""
+		if schemaKey.Default != nil {
+			defaultValue = "`" + escapeMarkdown(fmt.Sprint(schemaKey.getDefault())) + "`"
+		}
+		required := "no"
+		if schemaKey.Required {
+			required = "yes"
+		}
+		_, _ = fmt.Fprintf(builder, "| `%s` | %s | %s | %s | %s | %s |\n",
+			schemaKey.Key,
+			getTypeName(schemaKey.Type),
+			defaultValue,
+			required,
+			escapeMarkdown(getComponentIdentifier(schemaKey.Component)),
+			escapeMarkdown(schemaKey.Description),
+		)
+	}
+	return builder.String()
+}
+
+// JSONReference returns a JSON array with all keys of the schema.
+func JSONReference(schema []*SchemaKey) ([]byte, error) {
+	type item struct {
+		Key         string `json:"key"`
+		Type        string `json:"type"`
+		Default     any    `json:"default,omitempty"`
+		Required    bool   `json:"required"`
+		Secret      bool   `json:"secret,omitempty"`
+		Component   string `json:"component,omitempty"`
+		Description string `json:"description,omitempty"`
+	}
+	items := make([]item, 0, len(schema))
+	for _, schemaKey := range schema {
+		defaultValue := schemaKey.getDefault()
+		if duration, ok := defaultValue.(time.Duration); ok {
+			defaultValue = duration.String()
+		}
+		items = append(items, item{
+			Key:         schemaKey.Key,
+			Type:        getTypeName(schemaKey.Type),
+			Default:     defaultValue,
+			Required:    schemaKey.Required,
+			Secret:      schemaKey.Secret,
+			Component:   getComponentIdentifier(schemaKey.Component),
+			Description: schemaKey.Description,
+		})
+	}
+	return json.MarshalIndent(items, "", "  ")
+}
+
+// getDefault returns the default value that can be shown in the reference.
+func (s *SchemaKey) getDefault() any {
+	if s.Secret && s.Default != nil {
+		return SecretMask
+	}
+	return s.Default
+}
+
+func getTypeName(valueType string) string {
+	if valueType == "" {
+		return TypeAny
+	}
+	return valueType
+}
+
+func getComponentIdentifier(component componego.Component) string {
+	if component == nil {
+		return ""
+	}
+	return component.ComponentIdentifier()
+}
+
+func escapeMarkdown(value string) string {
+	return strings.NewReplacer("|", "\\|", "\r\n", "<br>", "\n", "<br>").Replace(value)
+}
@@ -0,0 +1,64 @@
+/*
+Copyright 2024-present Volodymyr Konstanchuk and contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"github.com/componego/componego"
+)
+
+type scope struct {
+	configProvider componego.ConfigProvider
+	prefix         string
+}
+
+// NewScope returns a view of the configuration in which all keys are relative to the prefix.
+// For example, the key "addr" inside the scope with the prefix "server" is the same as the key "server.addr".
+// The scope can be created inside another scope.
+func NewScope(configProvider componego.ConfigProvider, prefix string) componego.ConfigProvider {
+	return &scope{
+		configProvider: configProvider,
+		prefix:         prefix,
+	}
+}
+
+// ConfigValue belongs to interface componego.ConfigProvider.
+func (s *scope) ConfigValue(configKey string, processor componego.Processor) (any, error) {
+	return s.configProvider.ConfigValue(s.getKey(configKey), processor)
+}
+
+// ConfigKeys belongs to interface TreeProvider.
+func (s *scope) ConfigKeys(prefix string) ([]string, error) {
+	return Keys(s.configProvider, s.getKey(prefix))
+}
+
+// ConfigHas belongs to interface TreeProvider.
+func (s *scope) ConfigHas(configKey string) bool {
+	return Has(s.configProvider, s.getKey(configKey))
+}
+
+func (s *scope) getKey(configKey string) string {
+	if s.prefix == "" {
+		return configKey
+	} else if configKey == "" {
+		return s.prefix
+	}
+	return s.prefix + delimiter + configKey
+}
+
+var (
+	_ componego.ConfigProvider = (*scope)(nil)
+)
@@ -0,0 +1,365 @@
+/*
+Copyright 2024-present Volodymyr Konstanchuk and contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/componego/componego"
+	"github.com/componego/componego/impl/environment/managers/config/readers"
+	"github.com/componego/componego/libs/xerrors"
+)
+
+var (
+	ErrConfigSource = ErrConfigInit.WithMessage("config source error", "E0320")
+)
+
+// Source is an interface that describes a source of configuration values.
+// Keys that contain the delimiter are treated as paths to nested values.
+type Source interface {
+	// ReadSource returns the configuration values of the source.
+	ReadSource(appMode componego.ApplicationMode, options any) (map[string]any, error)
+}
+
+// SourceFunc allows a function to be used as a configuration source.
+type SourceFunc func(appMode componego.ApplicationMode, options any) (map[string]any, error)
+
+// ReadSource belongs to interface Source.
+func (s SourceFunc) ReadSource(appMode componego.ApplicationMode, options any) (map[string]any, error) {
+	return s(appMode, options)
+}
+
+// FileReader is a function that converts the content of a file into configuration values.
+type FileReader = func(data []byte) (map[string]any, error)
+
+// Settings contains the configuration values and the origins of these values.
+// The application returns it from ApplicationSettingsInit to make the origins available through GetOrigin.
+type Settings struct {
+	// Values are the configuration values.
+	Values map[string]any
+	// origins contains the origins of the values.
+	origins *provenance
+}
+
+// NewSettings returns the settings with the values. The origins of the values are unknown.
+func NewSettings(values map[string]any) *Settings {
+	return &Settings{
+		Values:  values,
+		origins: newProvenance(),
+	}
+}
+
+// ReadSource belongs to interface Source.
+// The settings can be used as a source of another call of LoadSources, and their origins are kept in this case.
+func (s *Settings) ReadSource(_ componego.ApplicationMode, _ any) (map[string]any, error) {
+	return s.Values, nil
+}
+
+// getOrigins returns the origins of the settings.
+func (s *Settings) getOrigins() *provenance {
+	if s.origins == nil {
+		// The settings were created without NewSettings.
+		s.origins = newProvenance()
+	}
+	return s.origins
+}
+
+// LoadSources reads all sources and merges their values. The values of the following sources take precedence.
+// Nested maps are merged recursively, and other values are replaced.
+// The result contains the source of each value, which is available through GetOrigin after the application starts.
+func LoadSources(appMode componego.ApplicationMode, options any, sources ...Source) (*Settings, error) {
+	settings := NewSettings(make(map[string]any))
+	for _, source := range sources {
+		values, err := source.ReadSource(appMode, options)
+		if err != nil {
+			return nil, ErrConfigSource.WithError(err, "E0321",
+				xerrors.NewOption("componego:config:source", source),
+			)
+		}
+		expandedValues := expandKeys(values)
+		mergeMaps(settings.Values, expandedValues)
+		if nestedSettings, ok := source.(*Settings); ok {
+			settings.origins.merge(nestedSettings.getOrigins())
+			continue
+		} else if values == nil {
+			continue
+		}
+		describe := getSourceDescriber(source, appMode)
+		settings.origins.setSource("", expandedValues, describe)
+	}
+	return settings, nil
+}
+
+// sourceDescriber is an interface that describes a source which knows where each value came from.
+type sourceDescriber interface {
+	describeSource(appMode componego.ApplicationMode, configKey string) string
+}
+
+type describedSource struct {
+	Source
+	describe func(appMode componego.ApplicationMode, configKey string) string
+}
+
+func (d *describedSource) describeSource(appMode componego.ApplicationMode, configKey string) string {
+	return d.describe(appMode, configKey)
+}
+
+// NewNamedSource returns the source with the name. The name is used as the origin of the values of the source.
+func NewNamedSource(name string, source Source) Source {
+	return &describedSource{
+		Source: source,
+		describe: func(_ componego.ApplicationMode, _ string) string {
+			return name
+		},
+	}
+}
+
+// getSourceDescriber returns a function that describes the origin of the value of the source by the key.
+func getSourceDescriber(source Source, appMode componego.ApplicationMode) func(configKey string) string {
+	switch castedSource := source.(type) {
+	case sourceDescriber:
+		return func(configKey string) string {
+			return castedSource.describeSource(appMode, configKey)
+		}
+	case fmt.Stringer:
+		name := castedSource.String()
+		return func(_ string) string {
+			return name
+		}
+	}
+	name := fmt.Sprintf("%T", source)
+	return func(_ string) string {
+		return name
+	}
+}
+
+type fileSource struct {
+	filename string
+	reader   FileReader
+	optional bool
+}
+
+// NewFileSource returns a source that reads the file. The file must exist.
+// JSON format is used if the reader is nil.
+func NewFileSource(filename string, reader FileReader) Source {
+	return &fileSource{
+		filename: filename,
+		reader:   reader,
+	}
+}
+
+// NewModeFileSource returns a source that reads the file of the current application mode.
+// The placeholder {mode} in the filename is replaced with production, developer or test.
+// The source is empty if the file does not exist.
+func NewModeFileSource(filenamePattern string, reader FileReader) Source {
+	newSource := func(appMode componego.ApplicationMode) *fileSource {
+		return &fileSource{
+			filename: strings.ReplaceAll(filenamePattern, "{mode}", getModeName(appMode)),
+			reader:   reader,
+			optional: true,
+		}
+	}
+	return &describedSource{
+		Source: SourceFunc(func(appMode componego.ApplicationMode, options any) (map[string]any, error) {
+			return newSource(appMode).ReadSource(appMode, options)
+		}),
+		describe: func(appMode componego.ApplicationMode, configKey string) string {
+			return newSource(appMode).describeSource(appMode, configKey)
+		},
+	}
+}
+
+func (f *fileSource) describeSource(_ componego.ApplicationMode, _ string) string {
+	if filename, err := filepath.Abs(filepath.Clean(f.filename)); err == nil {
+		return "file:" + filename
+	}
+	return "file:" + f.filename
+}
+
+// ReadSource belongs to interface Source.
+func (f *fileSource) ReadSource(_ componego.ApplicationMode, _ any) (map[string]any, error) {
+	// We get the full file name for a nice error in case of a file reading error.
+	filename, err := filepath.Abs(filepath.Clean(f.filename))
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		if f.optional && errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	reader := f.reader
+	if reader == nil {
+		reader = readers.JSON
+	}
+	settings, err := reader(data)
+	if err != nil {
+		return nil, ErrConfigSource.WithError(err, "E0322",
+			xerrors.NewOption("componego:config:filename", filename),
+		)
+	}
+	return settings, nil
+}
+
+// NewEnvSource returns a source that reads environment variables with the prefix.
+// The rest of the variable name is converted to lower case, and double underscores are replaced with the delimiter.
+// For example, APP_SERVER__PORT becomes server.port if the prefix is APP_.
+func NewEnvSource(prefix string) Source {
+	return &describedSource{
+		Source: newEnvSource(prefix),
+		describe: func(_ componego.ApplicationMode, configKey string) string {
+			// The name is restored from the key, so it is correct for variables in upper case.
+			return "env:" + prefix + strings.ToUpper(strings.ReplaceAll(configKey, delimiter, "__"))
+		},
+	}
+}
+
+func newEnvSource(prefix string) SourceFunc {
+	return SourceFunc(func(_ componego.ApplicationMode, _ any) (map[string]any, error) {
+		settings := make(map[string]any)
+		for _, variable := range os.Environ() {
+			name, value, _ := strings.Cut(variable, "=")
+			if !strings.HasPrefix(name, prefix) || len(name) == len(prefix) {
+				continue
+			}
+			key := strings.ReplaceAll(strings.ToLower(name[len(prefix):]), "__", delimiter)
+			settings[key] = value
+		}
+		return settings, nil
+	})
+}
+
+// NewFlagSource returns a source that reads command line arguments in the format <prefix><key>=<value>.
+// For example, --config.server.port=8080 if the prefix is --config.
+// The arguments are taken from the options of the application if they are a list of strings (the default behavior of the driver).
+func NewFlagSource(prefix string) Source {
+	return &describedSource{
+		Source: newFlagSource(prefix),
+		describe: func(_ componego.ApplicationMode, configKey string) string {
+			return "flag:" + prefix + configKey
+		},
+	}
+}
+
+func newFlagSource(prefix string) SourceFunc {
+	return SourceFunc(func(_ componego.ApplicationMode, options any) (map[string]any, error) {
+		settings := make(map[string]any)
+		args, ok := options.([]string)
+		if !ok {
+			return settings, nil
+		}
+		for _, arg := range args {
+			if !strings.HasPrefix(arg, prefix) {
+				continue
+			}
+			key, value, ok := strings.Cut(arg[len(prefix):], "=")
+			if !ok || key == "" {
+				continue
+			}
+			settings[key] = value
+		}
+		return settings, nil
+	})
+}
+
+// NewMapSource returns a source with the values. It can be used to override values in code.
+func NewMapSource(values map[string]any) Source {
+	return NewNamedSource("map", SourceFunc(func(_ componego.ApplicationMode, _ any) (map[string]any, error) {
+		return values, nil
+	}))
+}
+
+func getModeName(appMode componego.ApplicationMode) string {
+	switch appMode {
+	case componego.ProductionMode:
+		return "production"
+	case componego.DeveloperMode:
+		return "developer"
+	case componego.TestMode:
+		return "test"
+	}
+	return "unknown"
+}
+
+// expandKeys converts keys with the delimiter into nested maps.
+func expandKeys(values map[string]any) map[string]any {
+	result := make(map[string]any, len(values))
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	// Keys are sorted so that the result does not depend on the order of the map.
+	sort.Strings(keys)
+	for _, key := range keys {
+		value := values[key]
+		if nestedValues, ok := value.(map[string]any); ok {
+			value = expandKeys(nestedValues)
+		}
+		// Keys use the same syntax as ConfigValue, so escaped dots do not split the key.
+		// Keys that are not valid for this syntax are used as is.
+		parts := []string{key}
+		if segments, err := parseKey(key); err == nil {
+			parts = make([]string, len(segments))
+			for i, segment := range segments {
+				parts[i] = segment.name
+			}
+		}
+		current := result
+		for _, part := range parts[:len(parts)-1] {
+			nested, ok := current[part].(map[string]any)
+			if !ok {
+				nested = make(map[string]any)
+				current[part] = nested
+			}
+			current = nested
+		}
+		lastPart := parts[len(parts)-1]
+		if existingValues, ok := current[lastPart].(map[string]any); ok {
+			if nestedValues, ok := value.(map[string]any); ok {
+				mergeMaps(existingValues, nestedValues)
+				continue
+			}
+		}
+		current[lastPart] = value
+	}
+	return result
+}
+
+// mergeMaps merges the source into the destination. Maps of the source are copied.
+func mergeMaps(destination map[string]any, source map[string]any) {
+	for key, value := range source {
+		sourceMap, ok := value.(map[string]any)
+		if !ok {
+			destination[key] = value
+			continue
+		}
+		destinationMap, ok := destination[key].(map[string]any)
+		if !ok {
+			destinationMap = make(map[string]any, len(sourceMap))
+			destination[key] = destinationMap
+		}
+		mergeMaps(destinationMap, sourceMap)
+	}
+}
@@ -0,0 +1,167 @@
+/*
+Copyright 2024-present Volodymyr Konstanchuk and contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tests
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/componego/componego"
+	"github.com/componego/componego/impl/environment/managers/config"
+	"github.com/componego/componego/impl/processors"
+	"github.com/componego/componego/internal/testing/require"
+)
+
+type tlsConfig struct {
+	Enabled  bool   `default:"false"`
+	CertFile string `config:"cert"`
+}
+
+type endpointConfig struct {
+	Path    string `required:"true"`
+	Methods []string
+}
+
+type serverConfig struct {
+	Addr        string        `default:":3030"`
+	Port        uint16        `required:"true"`
+	ReadTimeout time.Duration `default:"1s"`
+	StartedAt   time.Time
+	Ratio       float32
+	TLS         tlsConfig `config:"tls"`
+	Proxy       *tlsConfig
+	Hosts       []string
+	Endpoints   []endpointConfig
+	Labels      map[string]string
+	Limits      map[string]int
+	Name        string `processors:"toString,upper"`
+	Extra       any
+	Ignored     string `config:"-"`
+	internal    string
+}
+
+func (s serverConfig) ConfigProcessors() map[string]componego.Processor {
+	return map[string]componego.Processor{
+		"upper": processors.New(func(value any) (any, error) {
+			if value == "" {
+				return nil, errors.New("the name is empty")
+			}
+			return value.(string) + "!", nil
+		}),
+	}
+}
+
+func TestBind(t *testing.T) {
+	t.Run("all types", func(t *testing.T) {
+		env := createEnvironment(t, map[string]any{
+			"server": map[string]any{
+				"port":        "8080",
+				"readTimeout": "5s",
+				"startedAt":   "2024-10-01T10:00:00Z",
+				"ratio":       0.5,
+				"tls": map[string]any{
+					"enabled": "true",
+					"cert":    "cert.pem",
+				},
+				"proxy": map[string]any{
+					"enabled": true,
+				},
+				"hosts": "a.com, b.com",
+				"endpoints": []any{
+					map[string]any{"path": "/", "methods": []any{"GET", "POST"}},
+				},
+				"labels":  map[string]any{"env": "test"},
+				"limits":  map[string]any{"rps": 100.0},
+				"name":    123,
+				"extra":   []any{1, 2},
+				"ignored": "value",
+			},
+		})
+		result, err := config.Bind[serverConfig](env, "server")
+		require.NoError(t, err)
+		require.Equal(t, serverConfig{
+			Addr:        ":3030",
+			Port:        8080,
+			ReadTimeout: 5 * time.Second,
+			StartedAt:   time.Date(2024, 10, 1, 10, 0, 0, 0, time.UTC),
+			Ratio:       0.5,
+			TLS:         tlsConfig{Enabled: true, CertFile: "cert.pem"},
+			Proxy:       &tlsConfig{Enabled: true},
+			Hosts:       []string{"a.com", "b.com"},
+			Endpoints: []endpointConfig{
+				{Path: "/", Methods: []string{"GET", "POST"}},
+			},
+			Labels: map[string]string{"env": "test"},
+			Limits: map[string]int{"rps": 100},
+			Name:   "123!",
+			Extra:  []any{1, 2},
+		}, result)
+	})
+
+	t.Run("all errors at once", func(t *testing.T) {
+		env := createEnvironment(t, map[string]any{
+			"server": map[string]any{
+				"readTimeout": 5,
+				"ratio":       "invalid",
+				"tls":         "invalid",
+				"endpoints":   []any{map[string]any{"methods": []any{}}},
+				"limits":      map[string]any{"rps": "many", "burst.max": "many"},
+				"name":        "",
+			},
+		})
+		_, err := config.Bind[serverConfig](env, "server")
+		require.ErrorIs(t, err, config.ErrBindField)
+		for _, key := range []string{
+			"server.port: the value is required",
+			"server.readTimeout: expected a duration",
+			"server.ratio: ",
+			"server.tls: expected an object",
+			"server.endpoints.0.path: the value is required",
+			"server.limits.rps: ",
+			`server.limits.burst\.max: `,
+			"server.name: the name is empty",
+		} {
+			require.ErrorContains(t, err, key)
+		}
+	})
+
+	t.Run("root and missing prefix", func(t *testing.T) {
+		env := createEnvironment(t, map[string]any{
+			"enabled": true,
+		})
+		result, err := config.Bind[tlsConfig](env, "")
+		require.NoError(t, err)
+		require.Equal(t, tlsConfig{Enabled: true}, result)
+		result, err = config.Bind[tlsConfig](env, "missing")
+		require.NoError(t, err)
+		require.Equal(t, tlsConfig{}, result)
+		_, err = config.Bind[endpointConfig](env, "missing")
+		require.ErrorContains(t, err, "missing.path: the value is required")
+		_, err = config.Bind[int](env, "")
+		require.ErrorIs(t, err, config.ErrBind)
+	})
+
+	t.Run("unknown processor", func(t *testing.T) {
+		type invalidConfig struct {
+			Value string `processors:"unknown"`
+		}
+		env := createEnvironment(t, nil)
+		_, err := config.Bind[invalidConfig](env, "")
+		require.ErrorContains(t, err, "value: unknown processor 'unknown'")
+	})
+}
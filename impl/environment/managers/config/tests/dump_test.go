@@ -0,0 +1,231 @@
+/*
+Copyright 2024-present Volodymyr Konstanchuk and contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/componego/componego"
+	"github.com/componego/componego/impl/application"
+	"github.com/componego/componego/impl/driver"
+	"github.com/componego/componego/impl/environment/managers/component"
+	"github.com/componego/componego/impl/environment/managers/config"
+	"github.com/componego/componego/internal/testing/require"
+)
+
+func TestDump(t *testing.T) {
+	createDumpEnvironment := func(t *testing.T, appMode componego.ApplicationMode) componego.Environment {
+		componentFactory := component.NewFactory("db", "1.0.0")
+		componentFactory.SetComponentConfigSchema(func() (string, []*componego.ConfigKey, error) {
+			return "db", []*componego.ConfigKey{
+				{Key: "dsn", Type: config.TypeString, Secret: true},
+				{Key: "pool", Type: config.TypeInt, Default: 10},
+			}, nil
+		})
+		appFactory := application.NewFactory("Config Dump Test Application")
+		appFactory.SetApplicationComponents(func() ([]componego.Component, error) {
+			return []componego.Component{componentFactory.Build()}, nil
+		})
+		app := newSettingsApplication(appFactory, func(appMode componego.ApplicationMode, _ any) (*config.Settings, error) {
+			settings, err := config.LoadSources(appMode, nil, config.NewNamedSource("app", config.NewMapSource(map[string]any{
+				"server": map[string]any{
+					"addr":  ":80",
+					"hosts": []any{"a", "b"},
+					"auth":  map[string]any{"apiToken": "t0ken", "users": []any{map[string]any{"password": "p"}}},
+				},
+				"db": map[string]any{"dsn": "postgres://user:${ENV:COMPONEGO_DUMP_PASSWORD|pass}@localhost"},
+			})))
+			if err != nil {
+				return nil, err
+			}
+			return settings, config.NewInterpolator().ProcessSettings(settings)
+		})
+		env, cancelEnv, err := driver.New(&driver.Options{
+			AppIO: application.NewIO(nil, io.Discard, io.Discard),
+		}).CreateEnvironment(context.Background(), app, appMode)
+		require.NoError(t, err)
+		t.Cleanup(func() {
+			require.NoError(t, cancelEnv())
+		})
+		return env
+	}
+	env := createDumpEnvironment(t, componego.TestMode)
+
+	t.Run("json", func(t *testing.T) {
+		dump, err := config.Dump(env, nil)
+		require.NoError(t, err)
+		settings := make(map[string]any)
+		require.NoError(t, json.Unmarshal([]byte(dump), &settings))
+		require.Equal(t, map[string]any{
+			"server": map[string]any{
+				"addr":  ":80",
+				"hosts": []any{"a", "b"},
+				"auth":  map[string]any{"apiToken": config.SecretMask, "users": []any{map[string]any{"password": config.SecretMask}}},
+			},
+			"db": map[string]any{"dsn": config.SecretMask, "pool": float64(10)},
+		}, settings)
+		// The configuration itself is not changed.
+		require.Equal(t, "t0ken", config.GetOrPanic[string]("server.auth.apiToken", nil, env))
+	})
+
+	t.Run("flat", func(t *testing.T) {
+		dump, err := config.Dump(env, &config.DumpOptions{
+			Format:         config.DumpFlat,
+			SecretPatterns: []string{"auth"},
+		})
+		require.NoError(t, err)
+		require.Equal(t, strings.Join([]string{
+			"db.dsn=" + config.SecretMask,
+			"db.pool=10",
+			"server.addr=:80",
+			"server.auth=" + config.SecretMask,
+			`server.hosts=["a","b"]`,
+		}, "\n")+"\n", dump)
+	})
+
+	t.Run("flat with origins", func(t *testing.T) {
+		dump, err := config.Dump(env, &config.DumpOptions{
+			Format:  config.DumpFlat,
+			Origins: true,
+		})
+		require.NoError(t, err)
+		require.Equal(t, strings.Join([]string{
+			`db.dsn=` + config.SecretMask + ` # app (raw value: "` + config.SecretMask + `", variables: ENV:COMPONEGO_DUMP_PASSWORD)`,
+			"db.pool=10 # default:db",
+			"server.addr=:80 # app",
+			"server.auth.apiToken=" + config.SecretMask + " # app",
+			`server.auth.users=[{"password":"` + config.SecretMask + `"}] # app`,
+			`server.hosts=["a","b"] # app`,
+		}, "\n")+"\n", dump)
+	})
+
+	t.Run("tree", func(t *testing.T) {
+		dump, err := config.Dump(env, &config.DumpOptions{
+			Format: config.DumpTree,
+		})
+		require.NoError(t, err)
+		require.Contains(t, dump, `"addr": ":80",`)
+		require.Contains(t, dump, `"dsn": "`+config.SecretMask+`",`)
+		require.Contains(t, dump, `0: "a",`)
+		require.False(t, strings.Contains(dump, "t0ken"))
+	})
+
+	t.Run("developer mode", func(t *testing.T) {
+		env := createDumpEnvironment(t, componego.DeveloperMode)
+		dump, err := config.Dump(env, nil)
+		require.NoError(t, err)
+		result := struct {
+			Config  map[string]any   `json:"config"`
+			Origins []*config.Origin `json:"origins"`
+		}{}
+		require.NoError(t, json.Unmarshal([]byte(dump), &result))
+		require.Equal(t, config.SecretMask, result.Config["db"].(map[string]any)["dsn"])
+		require.Len(t, result.Origins, 6)
+		require.Equal(t, &config.Origin{Key: "db.pool", Source: "default:db"}, result.Origins[1])
+	})
+
+	t.Run("typed values", func(t *testing.T) {
+		type credentials struct {
+			User     string `json:"user"`
+			Password string `json:"password"`
+		}
+		env := createEnvironment(t, map[string]any{
+			"basic":   map[string]string{"user": "admin", "password": "hunter2"},
+			"clients": []map[string]any{{"id": 1, "token": "tok123"}},
+			"ports":   map[int]int{80: 8080},
+			"admin":   &credentials{User: "root", Password: "r00t"},
+		})
+		dump, err := config.Dump(env, &config.DumpOptions{Format: config.DumpJSON})
+		require.NoError(t, err)
+		settings := make(map[string]any)
+		require.NoError(t, json.Unmarshal([]byte(dump), &settings))
+		require.Equal(t, map[string]any{
+			"basic":   map[string]any{"user": "admin", "password": config.SecretMask},
+			"clients": []any{map[string]any{"id": float64(1), "token": config.SecretMask}},
+			"ports":   map[string]any{"80": float64(8080)},
+			"admin":   map[string]any{"user": "root", "password": config.SecretMask},
+		}, settings)
+		dump, err = config.Dump(env, &config.DumpOptions{Format: config.DumpFlat})
+		require.NoError(t, err)
+		require.Equal(t, strings.Join([]string{
+			"admin.password=" + config.SecretMask,
+			"admin.user=root",
+			"basic.password=" + config.SecretMask,
+			"basic.user=admin",
+			`clients=[{"id":1,"token":"` + config.SecretMask + `"}]`,
+			"ports.80=8080",
+		}, "\n")+"\n", dump)
+		for _, secret := range []string{"hunter2", "tok123", "r00t"} {
+			require.False(t, strings.Contains(dump, secret), secret)
+		}
+	})
+
+	t.Run("interpolated secrets", func(t *testing.T) {
+		t.Setenv("COMPONEGO_DUMP_API_TOKEN", "tok456")
+		app := newSettingsApplication(application.NewFactory("Config Dump Secrets Test Application"),
+			func(appMode componego.ApplicationMode, _ any) (*config.Settings, error) {
+				settings, err := config.LoadSources(appMode, nil, config.NewMapSource(map[string]any{
+					"db":   map[string]any{"password": "hunter2", "url": "postgres://u:${CONFIG:db.password}@h"},
+					"api":  map[string]any{"header": "Bearer ${ENV:COMPONEGO_DUMP_API_TOKEN}"},
+					"copy": "${CONFIG:db.url}",
+				}))
+				if err != nil {
+					return nil, err
+				}
+				return settings, config.NewInterpolator().ProcessSettings(settings)
+			},
+		)
+		env, cancelEnv, err := driver.New(&driver.Options{
+			AppIO: application.NewIO(nil, io.Discard, io.Discard),
+		}).CreateEnvironment(context.Background(), app, componego.TestMode)
+		require.NoError(t, err)
+		t.Cleanup(func() {
+			require.NoError(t, cancelEnv())
+		})
+		dump, err := config.Dump(env, &config.DumpOptions{Format: config.DumpFlat})
+		require.NoError(t, err)
+		// Values that contain secret variables are masked, including values that reference them through other keys.
+		require.Equal(t, strings.Join([]string{
+			"api.header=" + config.SecretMask,
+			"copy=" + config.SecretMask,
+			"db.password=" + config.SecretMask,
+			"db.url=" + config.SecretMask,
+		}, "\n")+"\n", dump)
+		for _, secret := range []string{"hunter2", "tok456"} {
+			require.False(t, strings.Contains(dump, secret), secret)
+		}
+	})
+
+	t.Run("unknown format", func(t *testing.T) {
+		_, err := config.Dump(env, &config.DumpOptions{Format: "yaml"})
+		require.ErrorIs(t, err, config.ErrDump)
+	})
+
+	t.Run("schema reference", func(t *testing.T) {
+		componentFactory := component.NewFactory("secret", "1.0.0")
+		componentFactory.SetComponentConfigSchema(func() (string, []*componego.ConfigKey, error) {
+			return "", []*componego.ConfigKey{{Key: "key", Default: "value", Secret: true}}, nil
+		})
+		schema, err := config.GetSchema([]componego.Component{componentFactory.Build()})
+		require.NoError(t, err)
+		require.Contains(t, config.MarkdownReference(schema), "| `key` | any | `"+config.SecretMask+"` | no | secret |  |")
+	})
+}
@@ -0,0 +1,171 @@
+/*
+Copyright 2024-present Volodymyr Konstanchuk and contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tests
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/componego/componego/impl/environment/managers/config"
+	"github.com/componego/componego/internal/testing/require"
+)
+
+func TestInterpolation(t *testing.T) {
+	t.Run("environment variables", func(t *testing.T) {
+		t.Setenv("COMPONEGO_TEST_PORT", "1234")
+		settings := map[string]any{
+			"server": map[string]any{
+				"addr":    ":${ENV:COMPONEGO_TEST_PORT}",
+				"url":     "${ENV:COMPONEGO_TEST_URL|https://example.com/path?a=b}",
+				"nested":  "${ENV:COMPONEGO_TEST_URL|${ENV:COMPONEGO_TEST_PORT}}",
+				"escaped": "$${ENV:COMPONEGO_TEST_PORT}",
+				"hosts":   []any{"${ENV:COMPONEGO_TEST_PORT}", []any{"a-${ENV:COMPONEGO_TEST_PORT}"}, 1},
+			},
+		}
+		require.NoError(t, config.ProcessVariables(settings))
+		require.Equal(t, map[string]any{
+			"server": map[string]any{
+				"addr":    ":1234",
+				"url":     "https://example.com/path?a=b",
+				"nested":  "1234",
+				"escaped": "${ENV:COMPONEGO_TEST_PORT}",
+				"hosts":   []any{"1234", []any{"a-1234"}, 1},
+			},
+		}, settings)
+	})
+
+	t.Run("files", func(t *testing.T) {
+		directory := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(directory, "secret"), []byte("password\n"), 0o600))
+		// Files are not read by default.
+		settings := map[string]any{
+			"password": "${FILE:secret}",
+		}
+		require.NoError(t, config.ProcessVariables(settings))
+		require.Equal(t, "${FILE:secret}", settings["password"])
+		interpolator := config.NewInterpolator().AddSource("FILE", config.NewFileVariableSource(directory))
+		settings = map[string]any{
+			"password": "${FILE:secret}",
+			"missing":  "${FILE:secret.missing|default}",
+		}
+		require.NoError(t, interpolator.Process(settings))
+		require.Equal(t, map[string]any{
+			"password": "password",
+			"missing":  "default",
+		}, settings)
+		// Files outside the directory are not read even if the variable has a default value.
+		err := interpolator.Process(map[string]any{
+			"outside": "${FILE:../secret|default}",
+		})
+		require.ErrorIs(t, err, config.ErrInterpolation)
+		require.ErrorContains(t, err, "is outside the directory")
+		require.NoError(t, interpolator.Process(map[string]any{
+			"password": "${FILE:../" + filepath.Base(directory) + "/secret}",
+		}))
+	})
+
+	t.Run("config references", func(t *testing.T) {
+		settings := map[string]any{
+			"db": map[string]any{
+				"host": "localhost",
+				"port": 5432,
+				"url":  "postgres://${CONFIG:db.host}:${CONFIG:db.port}/${CONFIG:db.name|app}",
+			},
+			"port":    "${CONFIG:db.port}",
+			"primary": "${CONFIG:db}",
+			"escaped": "$${CONFIG:db.host}",
+			"copy":    "${CONFIG:escaped}",
+		}
+		require.NoError(t, config.ProcessVariables(settings))
+		require.Equal(t, "postgres://localhost:5432/app", settings["db"].(map[string]any)["url"])
+		require.Equal(t, 5432, settings["port"])
+		require.Equal(t, "postgres://localhost:5432/app", settings["primary"].(map[string]any)["url"])
+		require.Equal(t, "${CONFIG:db.host}", settings["escaped"])
+		require.Equal(t, "${CONFIG:db.host}", settings["copy"])
+	})
+
+	t.Run("custom sources", func(t *testing.T) {
+		interpolator := config.NewInterpolator().AddSource("VAULT", config.VariableSourceFunc(func(name string) (any, bool, error) {
+			if name == "broken" {
+				return nil, false, errors.New("vault is sealed")
+			}
+			return "secret:" + name, true, nil
+		}))
+		settings := map[string]any{
+			"token": "${VAULT:token}",
+		}
+		require.NoError(t, interpolator.Process(settings))
+		require.Equal(t, "secret:token", settings["token"])
+		err := interpolator.Process(map[string]any{"token": "${VAULT:broken}"})
+		require.ErrorIs(t, err, config.ErrInterpolation)
+		require.ErrorContains(t, err, "token: vault is sealed")
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		settings := map[string]any{
+			"a": "${CONFIG:b}",
+			"b": map[string]any{
+				"c": "${CONFIG:a}",
+			},
+			"self":     "${CONFIG:self}",
+			"list":     []any{"ok", map[string]any{"value": "${ENV:COMPONEGO_TEST_MISSING}"}},
+			"hosts":    map[string]any{"example.com": "${ENV:COMPONEGO_TEST_MISSING}"},
+			"unknown":  "${UNKNOWN:value}",
+			"invalid":  "${ENV}",
+			"unclosed": "${ENV:COMPONEGO_TEST_MISSING|${ENV:X}",
+		}
+		err := config.NewInterpolator().SetStrict(true).Process(settings)
+		require.ErrorIs(t, err, config.ErrInterpolation)
+		for _, message := range []string{
+			"a: b.c: cyclic reference a -> b -> a",
+			"b.c: a: cyclic reference b -> a -> b",
+			"self: cyclic reference self -> self",
+			`list.1.value: variable "ENV:COMPONEGO_TEST_MISSING" not found`,
+			`hosts.example\.com: variable "ENV:COMPONEGO_TEST_MISSING" not found`,
+			`unknown: unknown variable source "UNKNOWN"`,
+			`invalid: invalid variable "${ENV}"`,
+			`unclosed: unclosed variable`,
+		} {
+			require.ErrorContains(t, err, message)
+		}
+		// The configuration does not change if there are errors.
+		require.Equal(t, "${CONFIG:b}", settings["a"])
+	})
+
+	t.Run("unknown variables are left unchanged", func(t *testing.T) {
+		t.Setenv("COMPONEGO_TEST_HOST", "localhost")
+		settings := map[string]any{
+			"unknown":  "${UNKNOWN:value}",
+			"invalid":  "${HOME}/data",
+			"mixed":    "${ENV:COMPONEGO_TEST_HOST}:${PORT}",
+			"unclosed": "${ENV:COMPONEGO_TEST_HOST} ${ENV:X",
+		}
+		require.NoError(t, config.ProcessVariables(settings))
+		require.Equal(t, map[string]any{
+			"unknown":  "${UNKNOWN:value}",
+			"invalid":  "${HOME}/data",
+			"mixed":    "localhost:${PORT}",
+			"unclosed": "localhost ${ENV:X",
+		}, settings)
+		err := config.ProcessVariables(map[string]any{
+			"missing": "${ENV:COMPONEGO_TEST_MISSING}",
+		})
+		require.ErrorIs(t, err, config.ErrInterpolation)
+	})
+}
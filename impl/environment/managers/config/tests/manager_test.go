@@ -0,0 +1,292 @@
+/*
+Copyright 2024-present Volodymyr Konstanchuk and contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tests
+
+import (
+	"testing"
+
+	"github.com/componego/componego"
+	"github.com/componego/componego/impl/application"
+	"github.com/componego/componego/impl/environment/managers/component"
+	"github.com/componego/componego/impl/environment/managers/config"
+	"github.com/componego/componego/impl/processors"
+	"github.com/componego/componego/internal/testing/require"
+	"github.com/componego/componego/tests/runner"
+)
+
+func createEnvironment(t *testing.T, appConfig map[string]any, components ...componego.Component) componego.Environment {
+	appFactory := application.NewFactory("Config Test Application")
+	appFactory.SetApplicationConfigInit(func(_ componego.ApplicationMode, _ any) (map[string]any, error) {
+		return appConfig, nil
+	})
+	appFactory.SetApplicationComponents(func() ([]componego.Component, error) {
+		return components, nil
+	})
+	env, cancelEnv := runner.CreateTestEnvironment(t, appFactory.Build(), nil)
+	t.Cleanup(cancelEnv)
+	return env
+}
+
+// settingsApplication is an application that returns the configuration with the origins of values.
+type settingsApplication struct {
+	*application.QuickApplication
+	settingsInit func(appMode componego.ApplicationMode, options any) (*config.Settings, error)
+}
+
+func newSettingsApplication(
+	appFactory application.Factory,
+	settingsInit func(appMode componego.ApplicationMode, options any) (*config.Settings, error),
+) *settingsApplication {
+	return &settingsApplication{
+		QuickApplication: appFactory.Build().(*application.QuickApplication),
+		settingsInit:     settingsInit,
+	}
+}
+
+// ApplicationSettingsInit belongs to interface config.ApplicationSettingsInit.
+func (s *settingsApplication) ApplicationSettingsInit(appMode componego.ApplicationMode, options any) (*config.Settings, error) {
+	return s.settingsInit(appMode, options)
+}
+
+// reloadableApplication is an application whose configuration can be reloaded.
+type reloadableApplication struct {
+	*settingsApplication
+}
+
+func newReloadableApplication(
+	appFactory application.Factory,
+	settingsInit func(appMode componego.ApplicationMode, options any) (*config.Settings, error),
+) componego.Application {
+	return &reloadableApplication{
+		settingsApplication: newSettingsApplication(appFactory, settingsInit),
+	}
+}
+
+// ApplicationConfigReload belongs to interface config.ApplicationConfigReload.
+func (r *reloadableApplication) ApplicationConfigReload(appMode componego.ApplicationMode, options any) (*config.Settings, error) {
+	return r.settingsInit(appMode, options)
+}
+
+func newComponentWithDefaults(identifier string, namespace string, defaults map[string]any) componego.Component {
+	componentFactory := component.NewFactory(identifier, "0.0.1")
+	componentFactory.SetComponentConfigDefaults(func() (string, map[string]any, error) {
+		return namespace, defaults, nil
+	})
+	return componentFactory.Build()
+}
+
+func TestComponentDefaults(t *testing.T) {
+	env := createEnvironment(t,
+		map[string]any{
+			"server": map[string]any{
+				"port": 8080,
+			},
+			"server.tls.enabled": true,
+			"database":           "application value",
+		},
+		newComponentWithDefaults("server", "server", map[string]any{
+			"addr": "localhost",
+			"port": 80,
+			"tls": map[string]any{
+				"enabled": false,
+				"cert":    "cert.pem",
+			},
+		}),
+		newComponentWithDefaults("database", "database", map[string]any{
+			"dsn": "default",
+		}),
+		newComponentWithDefaults("cache", "services.cache", map[string]any{
+			"size": 100,
+		}),
+	)
+	testCases := [...]struct {
+		configKey string
+		value     any
+	}{
+		{"server.addr", "localhost"},
+		{"server.port", 8080},
+		{"server.tls.enabled", true},
+		{"server.tls.cert", "cert.pem"},
+		{"database", "application value"},
+		{"services.cache.size", 100},
+	}
+	for _, testCase := range testCases {
+		value, err := env.ConfigProvider().ConfigValue(testCase.configKey, nil)
+		require.NoError(t, err, testCase.configKey)
+		require.Equal(t, testCase.value, value, testCase.configKey)
+	}
+}
+
+func TestScope(t *testing.T) {
+	env := createEnvironment(t, map[string]any{
+		"servers": map[string]any{
+			"public": map[string]any{
+				"addr": ":80",
+			},
+		},
+	})
+	scope := config.NewScope(env.ConfigProvider(), "servers")
+	value, err := scope.ConfigValue("public.addr", nil)
+	require.NoError(t, err)
+	require.Equal(t, ":80", value)
+	nestedScope := config.NewScope(scope, "public")
+	value, err = nestedScope.ConfigValue("addr", nil)
+	require.NoError(t, err)
+	require.Equal(t, ":80", value)
+	value, err = nestedScope.ConfigValue("", nil)
+	require.NoError(t, err)
+	require.Equal(t, map[string]any{"addr": ":80"}, value)
+	_, err = nestedScope.ConfigValue("port", nil)
+	require.ErrorIs(t, err, config.ErrValueNotFound)
+	require.ErrorContains(t, err, "E0314")
+}
+
+func TestConfigKeys(t *testing.T) {
+	env := createEnvironment(t, map[string]any{
+		"servers": []any{
+			map[string]any{"addr": ":80", "tags": []string{"a", "b"}},
+			map[string]any{"addr": ":81"},
+			map[string]any{"port": 82},
+		},
+		"hosts": map[string]any{
+			"example.com": map[string]any{"enabled": true},
+			"*":           "asterisk",
+			"a\\b":        "backslash",
+		},
+		"name": "app",
+		// Flat keys are found even if they are not valid in the key syntax.
+		"paths.c:\\temp": "flat backslash",
+		"paths..hidden":  "flat dots",
+	})
+	testCases := [...]struct {
+		configKey string
+		value     any
+	}{
+		{"servers.0.addr", ":80"},
+		{"servers.1.addr", ":81"},
+		{"servers.0.tags.1", "b"},
+		{"servers.*.addr", []any{":80", ":81"}},
+		{"servers.*.tags.*", []any{[]any{"a", "b"}}},
+		{"hosts.example\\.com.enabled", true},
+		{"hosts.\\*", "asterisk"},
+		{"hosts.a\\\\b", "backslash"},
+		{"hosts.*.enabled", []any{true}},
+		{"paths.c:\\temp", "flat backslash"},
+		{"paths..hidden", "flat dots"},
+	}
+	for _, testCase := range testCases {
+		value, err := env.ConfigProvider().ConfigValue(testCase.configKey, nil)
+		require.NoError(t, err, testCase.configKey)
+		require.Equal(t, testCase.value, value, testCase.configKey)
+	}
+	for _, configKey := range []string{
+		"servers.3.addr",
+		"servers.01.addr",
+		"servers.-1.addr",
+		"servers.addr",
+		"name.first",
+		"name.0",
+		"servers.0.addr.value",
+		"name.*",
+	} {
+		_, err := env.ConfigProvider().ConfigValue(configKey, nil)
+		require.ErrorIs(t, err, config.ErrValueNotFound, configKey)
+	}
+	for _, configKey := range []string{"servers..addr", "servers.", "hosts.\\x", "hosts\\"} {
+		_, err := env.ConfigProvider().ConfigValue(configKey, nil)
+		require.ErrorIs(t, err, config.ErrInvalidKey, configKey)
+		// The processor can return the default value for any key.
+		value, err := env.ConfigProvider().ConfigValue(configKey, processors.DefaultValue("default"))
+		require.NoError(t, err, configKey)
+		require.Equal(t, "default", value, configKey)
+	}
+	// An empty key does not mean the whole configuration.
+	_, err := env.ConfigProvider().ConfigValue("", nil)
+	require.ErrorIs(t, err, config.ErrValueNotFound)
+	require.ErrorContains(t, err, "E0314")
+	values, ok := config.AllValues(env)
+	require.True(t, ok)
+	require.Equal(t, "app", values["name"])
+	require.Equal(t, "a\\\\b\\.c\\*", config.EscapeKey("a\\b.c*"))
+}
+
+type valueProvider map[string]any
+
+func (v valueProvider) ConfigValue(configKey string, _ componego.Processor) (any, error) {
+	if value, ok := v[configKey]; ok {
+		return value, nil
+	}
+	return nil, config.ErrValueNotFound
+}
+
+func TestConfigTree(t *testing.T) {
+	env := createEnvironment(t, map[string]any{
+		"databases": map[string]any{
+			"main":       map[string]any{"dsn": "postgres://main"},
+			"replica":    map[string]any{"dsn": "postgres://replica"},
+			"legacy.db":  map[string]any{"dsn": "mysql://legacy"},
+			"connection": []any{"a", "b"},
+		},
+		"name": "app",
+	})
+	keys, err := config.Keys(env.ConfigProvider(), "databases")
+	require.NoError(t, err)
+	require.Equal(t, []string{"connection", "legacy\\.db", "main", "replica"}, keys)
+	for _, key := range keys {
+		require.True(t, config.Has(env.ConfigProvider(), "databases."+key), key)
+	}
+	keys, err = config.Keys(env.ConfigProvider(), "databases.connection")
+	require.NoError(t, err)
+	require.Equal(t, []string{"0", "1"}, keys)
+	keys, err = config.Keys(env.ConfigProvider(), "")
+	require.NoError(t, err)
+	require.Equal(t, []string{"databases", "name"}, keys)
+	require.False(t, config.Has(env.ConfigProvider(), "databases.missing"))
+	require.False(t, config.Has(env.ConfigProvider(), "name.first"))
+	_, err = config.Keys(env.ConfigProvider(), "databases.missing")
+	require.ErrorIs(t, err, config.ErrValueNotFound)
+	_, err = config.Keys(env.ConfigProvider(), "name")
+	require.ErrorIs(t, err, config.ErrNoChildKeys)
+
+	sub, err := config.Sub(env.ConfigProvider(), "databases")
+	require.NoError(t, err)
+	require.True(t, sub.ConfigHas("main.dsn"))
+	keys, err = sub.ConfigKeys("legacy\\.db")
+	require.NoError(t, err)
+	require.Equal(t, []string{"dsn"}, keys)
+	value, err := sub.ConfigValue("replica.dsn", nil)
+	require.NoError(t, err)
+	require.Equal(t, "postgres://replica", value)
+	_, err = config.Sub(env.ConfigProvider(), "name")
+	require.ErrorIs(t, err, config.ErrNoChildKeys)
+	_, err = config.Sub(env.ConfigProvider(), "missing")
+	require.ErrorIs(t, err, config.ErrValueNotFound)
+
+	// Custom providers are supported through method ConfigValue.
+	provider := valueProvider{
+		"servers": map[string]any{"public": ":80", "private": ":8080"},
+	}
+	keys, err = config.Keys(provider, "servers")
+	require.NoError(t, err)
+	require.Equal(t, []string{"private", "public"}, keys)
+	require.True(t, config.Has(provider, "servers"))
+	require.False(t, config.Has(provider, "clients"))
+	sub, err = config.Sub(provider, "servers")
+	require.NoError(t, err)
+	require.True(t, sub.ConfigHas(""))
+	require.False(t, sub.ConfigHas("public"))
+}
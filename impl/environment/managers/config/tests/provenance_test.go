@@ -0,0 +1,208 @@
+/*
+Copyright 2024-present Volodymyr Konstanchuk and contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tests
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/componego/componego"
+	"github.com/componego/componego/impl/application"
+	"github.com/componego/componego/impl/environment/managers/component"
+	"github.com/componego/componego/impl/environment/managers/config"
+	"github.com/componego/componego/impl/processors"
+	"github.com/componego/componego/internal/testing/require"
+	"github.com/componego/componego/libs/xerrors"
+	"github.com/componego/componego/tests/runner"
+)
+
+func TestProvenance(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "config.json")
+	require.NoError(t, os.WriteFile(filename, []byte(`{
+		"server": {"addr": ":80", "port": 80, "hosts": ["a", "b"]},
+		"db": {"url": "postgres://${ENV:COMPONEGO_TEST_DB_HOST}/${ENV:COMPONEGO_TEST_DB_NAME|app}"}
+	}`), 0o600))
+	t.Setenv("COMPONEGO_PROVENANCE_SERVER__PORT", "8080")
+	t.Setenv("COMPONEGO_TEST_DB_HOST", "localhost")
+	loadConfig := func(override string) (*config.Settings, error) {
+		settings, err := config.LoadSources(componego.TestMode, nil,
+			config.NewFileSource(filename, nil),
+			config.NewEnvSource("COMPONEGO_PROVENANCE_"),
+			config.NewNamedSource("vault", config.NewMapSource(map[string]any{"db.password": override})),
+		)
+		if err != nil {
+			return nil, err
+		}
+		return settings, config.NewInterpolator().ProcessSettings(settings)
+	}
+	override := "secret"
+	appFactory := application.NewFactory("Config Provenance Test Application")
+	appFactory.SetApplicationComponents(func() ([]componego.Component, error) {
+		return []componego.Component{
+			newComponentWithDefaults("server", "server", map[string]any{
+				"addr":    ":3030",
+				"timeout": "1s",
+			}),
+		}, nil
+	})
+	app := newReloadableApplication(appFactory, func(_ componego.ApplicationMode, _ any) (*config.Settings, error) {
+		return loadConfig(override)
+	})
+	env, cancelEnv := runner.CreateTestEnvironment(t, app, nil)
+	t.Cleanup(cancelEnv)
+
+	absFilename, err := filepath.Abs(filename)
+	require.NoError(t, err)
+	testCases := [...]struct {
+		configKey string
+		origin    *config.Origin
+	}{
+		{"server.addr", &config.Origin{Key: "server.addr", Source: "file:" + absFilename}},
+		{"server.port", &config.Origin{Key: "server.port", Source: "env:COMPONEGO_PROVENANCE_SERVER__PORT"}},
+		{"server.timeout", &config.Origin{Key: "server.timeout", Source: "default:server"}},
+		{"server.hosts.1", &config.Origin{Key: "server.hosts", Source: "file:" + absFilename}},
+		{"db.password", &config.Origin{Key: "db.password", Source: "vault"}},
+		{"db.url", &config.Origin{
+			Key:       "db.url",
+			Source:    "file:" + absFilename,
+			RawValue:  "postgres://${ENV:COMPONEGO_TEST_DB_HOST}/${ENV:COMPONEGO_TEST_DB_NAME|app}",
+			Variables: []string{"ENV:COMPONEGO_TEST_DB_HOST", "ENV:COMPONEGO_TEST_DB_NAME"},
+		}},
+	}
+	for _, testCase := range testCases {
+		origin, ok := config.GetOrigin(env, testCase.configKey)
+		require.True(t, ok, testCase.configKey)
+		require.Equal(t, testCase.origin, origin, testCase.configKey)
+	}
+	_, ok := config.GetOrigin(env, "server")
+	require.False(t, ok)
+	_, ok = config.GetOrigin(env, "server.missing")
+	require.False(t, ok)
+	require.Equal(t,
+		`file:`+absFilename+` (raw value: "postgres://${ENV:COMPONEGO_TEST_DB_HOST}/${ENV:COMPONEGO_TEST_DB_NAME|app}", `+
+			`variables: ENV:COMPONEGO_TEST_DB_HOST, ENV:COMPONEGO_TEST_DB_NAME)`,
+		testCases[5].origin.String(),
+	)
+
+	t.Run("all origins", func(t *testing.T) {
+		keys := make([]string, 0)
+		for _, origin := range config.GetOrigins(env) {
+			keys = append(keys, origin.Key)
+		}
+		require.Equal(t, []string{"db.password", "db.url", "server.addr", "server.hosts", "server.port", "server.timeout"}, keys)
+	})
+
+	t.Run("processor", func(t *testing.T) {
+		value, err := env.ConfigProvider().ConfigValue("server.workers", processors.DefaultValue(4))
+		require.NoError(t, err)
+		require.Equal(t, 4, value)
+		origin, ok := config.GetOrigin(env, "server.workers")
+		require.True(t, ok)
+		require.Equal(t, config.OriginProcessor, origin.Source)
+	})
+
+	t.Run("reload", func(t *testing.T) {
+		override = "${ENV:COMPONEGO_TEST_DB_HOST}"
+		require.NoError(t, config.Reload(env))
+		origin, ok := config.GetOrigin(env, "db.password")
+		require.True(t, ok)
+		require.Equal(t, &config.Origin{
+			Key:       "db.password",
+			Source:    "vault",
+			RawValue:  "${ENV:COMPONEGO_TEST_DB_HOST}",
+			Variables: []string{"ENV:COMPONEGO_TEST_DB_HOST"},
+		}, origin)
+	})
+
+	t.Run("error options", func(t *testing.T) {
+		// The failed processor is used again during reloading, so this test is run after the reload test.
+		_, err := env.ConfigProvider().ConfigValue("server.port", processors.New(func(_ any) (any, error) {
+			return nil, errors.New("invalid port")
+		}))
+		require.ErrorIs(t, err, config.ErrConfigGet)
+		origins := make([]any, 0)
+		for _, err := range xerrors.UnwrapAll(err) {
+			xErr, ok := err.(xerrors.XError) //nolint:errorlint
+			if !ok {
+				continue
+			}
+			for _, option := range xErr.ErrorOptions() {
+				if option.Key() == "componego:config:origin" {
+					origins = append(origins, option.Value())
+				}
+			}
+		}
+		require.Equal(t, []any{
+			&config.Origin{Key: "server.port", Source: "env:COMPONEGO_PROVENANCE_SERVER__PORT"},
+		}, origins)
+	})
+
+	t.Run("cached configuration", func(t *testing.T) {
+		settings, err := loadConfig("cached")
+		require.NoError(t, err)
+		app := newReloadableApplication(application.NewFactory("Cached Config Provenance Test Application"),
+			func(_ componego.ApplicationMode, _ any) (*config.Settings, error) {
+				// The same settings are returned each time, so the manager must not modify their origins.
+				return settings, nil
+			},
+		)
+		env, cancelEnv := runner.CreateTestEnvironment(t, app, nil)
+		t.Cleanup(cancelEnv)
+		_, err = env.ConfigProvider().ConfigValue("server.workers", processors.DefaultValue(4))
+		require.NoError(t, err)
+		require.NoError(t, config.Reload(env))
+		origin, ok := config.GetOrigin(env, "db.password")
+		require.True(t, ok)
+		require.Equal(t, &config.Origin{Key: "db.password", Source: "vault"}, origin)
+		// The origin of the processor is not added to the cached configuration.
+		_, ok = config.GetOrigin(env, "server.workers")
+		require.False(t, ok)
+	})
+
+	t.Run("nested sources", func(t *testing.T) {
+		nestedSettings, err := config.LoadSources(componego.TestMode, nil,
+			config.NewNamedSource("nested", config.NewMapSource(map[string]any{"a": 1, "b": 2})),
+		)
+		require.NoError(t, err)
+		app := newSettingsApplication(application.NewFactory("Nested Config Provenance Test Application"),
+			func(appMode componego.ApplicationMode, options any) (*config.Settings, error) {
+				return config.LoadSources(appMode, options,
+					nestedSettings,
+					config.NewNamedSource("override", config.NewMapSource(map[string]any{"b": 3})),
+				)
+			},
+		)
+		env, cancelEnv := runner.CreateTestEnvironment(t, app, nil)
+		t.Cleanup(cancelEnv)
+		origin, ok := config.GetOrigin(env, "a")
+		require.True(t, ok)
+		require.Equal(t, &config.Origin{Key: "a", Source: "nested"}, origin)
+		origin, ok = config.GetOrigin(env, "b")
+		require.True(t, ok)
+		require.Equal(t, &config.Origin{Key: "b", Source: "override"}, origin)
+	})
+
+	t.Run("application", func(t *testing.T) {
+		componentFactory := component.NewFactory("cache", "1.0.0")
+		env := createEnvironment(t, map[string]any{"cache": map[string]any{"size": 10}}, componentFactory.Build())
+		origin, ok := config.GetOrigin(env, "cache.size")
+		require.True(t, ok)
+		require.Equal(t, &config.Origin{Key: "cache.size", Source: config.OriginApplication}, origin)
+	})
+}
@@ -0,0 +1,250 @@
+/*
+Copyright 2024-present Volodymyr Konstanchuk and contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tests
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/componego/componego"
+	"github.com/componego/componego/impl/application"
+	"github.com/componego/componego/impl/environment/managers/component"
+	"github.com/componego/componego/impl/environment/managers/config"
+	"github.com/componego/componego/impl/processors"
+	"github.com/componego/componego/internal/testing/require"
+	"github.com/componego/componego/tests/runner"
+)
+
+func TestReload(t *testing.T) {
+	createReloadableEnvironment := func(t *testing.T, loader func() (map[string]any, error), components ...componego.Component) componego.Environment {
+		appFactory := application.NewFactory("Config Reload Test Application")
+		appFactory.SetApplicationComponents(func() ([]componego.Component, error) {
+			return components, nil
+		})
+		app := newReloadableApplication(appFactory, func(_ componego.ApplicationMode, _ any) (*config.Settings, error) {
+			values, err := loader()
+			if err != nil {
+				return nil, err
+			}
+			return config.NewSettings(values), nil
+		})
+		env, cancelEnv := runner.CreateTestEnvironment(t, app, nil)
+		t.Cleanup(cancelEnv)
+		return env
+	}
+
+	t.Run("subscriptions", func(t *testing.T) {
+		appConfig := map[string]any{
+			"server": map[string]any{"port": 80, "host": "localhost"},
+			"db":     map[string]any{"url": "postgres://localhost"},
+		}
+		env := createReloadableEnvironment(t, func() (map[string]any, error) {
+			return appConfig, nil
+		})
+		changes := make([]*config.Change, 0)
+		_, err := config.Subscribe(env, "server", func(change *config.Change) {
+			changes = append(changes, change)
+		})
+		require.NoError(t, err)
+		unsubscribe, err := config.Subscribe(env, "db", func(change *config.Change) {
+			changes = append(changes, change)
+		})
+		require.NoError(t, err)
+		appConfig = map[string]any{
+			"server": map[string]any{"port": 8080, "host": "localhost", "tls": true},
+			"db":     map[string]any{"url": "postgres://localhost"},
+		}
+		require.NoError(t, config.Reload(env))
+		require.Equal(t, []*config.Change{
+			{Prefix: "server", Keys: []string{"server.port", "server.tls"}},
+		}, changes)
+		require.Equal(t, 8080, config.GetOrPanic[int]("server.port", nil, env))
+		unsubscribe()
+		appConfig = map[string]any{
+			"db": map[string]any{"url": "mysql://localhost"},
+		}
+		require.NoError(t, config.Reload(env))
+		require.Len(t, changes, 2)
+		require.Equal(t, &config.Change{
+			Prefix: "server",
+			Keys:   []string{"server"},
+		}, changes[1])
+	})
+
+	t.Run("validation", func(t *testing.T) {
+		appConfig := map[string]any{"port": "80"}
+		env := createReloadableEnvironment(t, func() (map[string]any, error) {
+			return appConfig, nil
+		})
+		value, err := env.ConfigProvider().ConfigValue("port", processors.ToInt64())
+		require.NoError(t, err)
+		require.Equal(t, int64(80), value)
+		appConfig = map[string]any{"port": "invalid"}
+		err = config.Reload(env)
+		require.ErrorIs(t, err, config.ErrConfigReload)
+		// The previous configuration is used if the new one is invalid.
+		require.Equal(t, "80", config.GetOrPanic[string]("port", nil, env))
+	})
+
+	t.Run("validation by all processors", func(t *testing.T) {
+		appConfig := map[string]any{"port": "80"}
+		env := createReloadableEnvironment(t, func() (map[string]any, error) {
+			return appConfig, nil
+		})
+		portProcessor := processors.New(func(value any) (any, error) {
+			if value == "8080" {
+				return nil, errors.New("port is not allowed")
+			}
+			return value, nil
+		})
+		_, err := env.ConfigProvider().ConfigValue("port", portProcessor)
+		require.NoError(t, err)
+		// The first processor is not forgotten even if the key is read many times with new processors.
+		for i := 0; i < 10; i++ {
+			_, err = env.ConfigProvider().ConfigValue("port", processors.ToInt64())
+			require.NoError(t, err)
+		}
+		appConfig = map[string]any{"port": "8080"}
+		require.ErrorIs(t, config.Reload(env), config.ErrConfigReload)
+		appConfig = map[string]any{"port": "invalid"}
+		require.ErrorIs(t, config.Reload(env), config.ErrConfigReload)
+		appConfig = map[string]any{"port": "443"}
+		require.NoError(t, config.Reload(env))
+		require.Equal(t, "443", config.GetOrPanic[string]("port", nil, env))
+	})
+
+	t.Run("reload inside subscriber", func(t *testing.T) {
+		appConfig := map[string]any{"key": 1}
+		env := createReloadableEnvironment(t, func() (map[string]any, error) {
+			return appConfig, nil
+		})
+		calls := 0
+		_, err := config.Subscribe(env, "key", func(_ *config.Change) {
+			calls++
+			if calls == 1 {
+				appConfig = map[string]any{"key": 3}
+				require.NoError(t, config.Reload(env))
+			}
+		})
+		require.NoError(t, err)
+		appConfig = map[string]any{"key": 2}
+		require.NoError(t, config.Reload(env))
+		require.Equal(t, 2, calls)
+		require.Equal(t, 3, config.GetOrPanic[int]("key", nil, env))
+	})
+
+	t.Run("static config", func(t *testing.T) {
+		appConfig := map[string]any{"server": map[string]any{"port": 80}, "level": "info"}
+		componentFactory := component.NewFactory("component", "0.0.1")
+		componentFactory.SetComponentConfigStatic(func() []string {
+			return []string{"server"}
+		})
+		componentFactory.SetComponentConfigDefaults(func() (string, map[string]any, error) {
+			return "server", map[string]any{"host": "localhost"}, nil
+		})
+		env := createReloadableEnvironment(t, func() (map[string]any, error) {
+			return appConfig, nil
+		}, componentFactory.Build())
+		appConfig = map[string]any{"server": map[string]any{"port": 80}, "level": "debug"}
+		require.NoError(t, config.Reload(env))
+		require.Equal(t, "debug", config.GetOrPanic[string]("level", nil, env))
+		// Default values are added to the new configuration.
+		require.Equal(t, "localhost", config.GetOrPanic[string]("server.host", nil, env))
+		appConfig = map[string]any{"server": map[string]any{"port": 8080}, "level": "debug"}
+		require.ErrorIs(t, config.Reload(env), config.ErrStaticConfigChanged)
+		require.Equal(t, 80, config.GetOrPanic[int]("server.port", nil, env))
+	})
+
+	t.Run("subscriber panic", func(t *testing.T) {
+		appConfig := map[string]any{"key": 1}
+		env := createReloadableEnvironment(t, func() (map[string]any, error) {
+			return appConfig, nil
+		})
+		_, err := config.Subscribe(env, "", func(_ *config.Change) {
+			panic("panic inside subscriber")
+		})
+		require.NoError(t, err)
+		appConfig = map[string]any{"key": 2}
+		require.ErrorIs(t, config.Reload(env), config.ErrConfigReload)
+		require.Equal(t, 2, config.GetOrPanic[int]("key", nil, env))
+	})
+
+	t.Run("file watcher", func(t *testing.T) {
+		filename := filepath.Join(t.TempDir(), "config.json")
+		require.NoError(t, os.WriteFile(filename, []byte(`{"key": "value 1"}`), 0o600))
+		env := createReloadableEnvironment(t, func() (map[string]any, error) {
+			settings, err := config.LoadSources(componego.TestMode, nil, config.NewFileSource(filename, nil))
+			if err != nil {
+				return nil, err
+			}
+			return settings.Values, nil
+		})
+		changed := make(chan struct{}, 1)
+		_, err := config.Subscribe(env, "key", func(_ *config.Change) {
+			changed <- struct{}{}
+		})
+		require.NoError(t, err)
+		ctx, cancelCtx := context.WithCancel(context.Background())
+		waitGroup := sync.WaitGroup{}
+		waitGroup.Add(1)
+		go func() {
+			defer waitGroup.Done()
+			_ = config.Watch(ctx, env, &config.WatchOptions{
+				Filenames: []string{filename},
+				Interval:  10 * time.Millisecond,
+			})
+		}()
+		defer func() {
+			cancelCtx()
+			waitGroup.Wait()
+		}()
+		// The file is changed until the watcher notices it, because the watcher may start after the first change.
+		timeout := time.After(5 * time.Second)
+		for value := "new value"; ; value += "!" {
+			require.NoError(t, os.WriteFile(filename, []byte(`{"key": "`+value+`"}`), 0o600))
+			select {
+			case <-changed:
+				require.Contains(t, config.GetOrPanic[string]("key", nil, env), "new value")
+				return
+			case <-time.After(50 * time.Millisecond):
+			case <-timeout:
+				require.FailNow(t, "the configuration was not reloaded")
+			}
+		}
+	})
+
+	t.Run("not supported", func(t *testing.T) {
+		countCalls := 0
+		appFactory := application.NewFactory("Config Reload Test Application")
+		appFactory.SetApplicationConfigInit(func(_ componego.ApplicationMode, _ any) (map[string]any, error) {
+			countCalls++
+			return nil, nil
+		})
+		env, cancelEnv := runner.CreateTestEnvironment(t, appFactory.Build(), nil)
+		t.Cleanup(cancelEnv)
+		// The application does not implement config.ApplicationConfigReload.
+		require.ErrorIs(t, config.Reload(env), config.ErrReloadNotSupported)
+		require.Equal(t, 1, countCalls)
+		_, err := config.Subscribe(env, "", func(_ *config.Change) {})
+		require.NoError(t, err)
+	})
+}
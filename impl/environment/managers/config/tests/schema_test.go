@@ -0,0 +1,205 @@
+/*
+Copyright 2024-present Volodymyr Konstanchuk and contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/componego/componego"
+	"github.com/componego/componego/impl/application"
+	"github.com/componego/componego/impl/driver"
+	"github.com/componego/componego/impl/environment/managers/component"
+	"github.com/componego/componego/impl/environment/managers/config"
+	"github.com/componego/componego/impl/processors"
+	"github.com/componego/componego/internal/testing/require"
+)
+
+func TestSchema(t *testing.T) {
+	newServerComponent := func() componego.Component {
+		componentFactory := component.NewFactory("server", "1.0.0")
+		componentFactory.SetComponentConfigSchema(func() (string, []*componego.ConfigKey, error) {
+			return "server", []*componego.ConfigKey{
+				{Key: "addr", Type: config.TypeString, Default: ":3030", Description: "Address | host and port"},
+				{Key: "port", Type: config.TypeInt, Required: true, Description: "Port number"},
+				{Key: "timeout", Type: config.TypeDuration, Default: time.Second},
+				{Key: "tls.enabled", Type: config.TypeBool},
+				{Key: "hosts", Type: config.TypeList},
+				{
+					Key:  "workers",
+					Type: config.TypeInt,
+					Processors: []componego.Processor{
+						processors.New(func(value any) (any, error) {
+							if value == "0" || value == 0 {
+								return nil, errors.New("must be positive")
+							}
+							return value, nil
+						}),
+					},
+				},
+			}, nil
+		})
+		return componentFactory.Build()
+	}
+	createEnvironment := func(t *testing.T, appConfig map[string]any, components ...componego.Component) (componego.Environment, error) {
+		appFactory := application.NewFactory("Config Schema Test Application")
+		appFactory.SetApplicationConfigInit(func(_ componego.ApplicationMode, _ any) (map[string]any, error) {
+			return appConfig, nil
+		})
+		appFactory.SetApplicationComponents(func() ([]componego.Component, error) {
+			return components, nil
+		})
+		d := driver.New(&driver.Options{
+			AppIO: application.NewIO(nil, io.Discard, io.Discard),
+		})
+		env, cancelEnv, err := d.CreateEnvironment(context.Background(), appFactory.Build(), componego.TestMode)
+		if err == nil {
+			t.Cleanup(func() {
+				require.NoError(t, cancelEnv())
+			})
+		}
+		return env, err
+	}
+
+	t.Run("valid configuration", func(t *testing.T) {
+		env, err := createEnvironment(t, map[string]any{
+			"server": map[string]any{
+				"port":    "8080",
+				"hosts":   "a,b",
+				"workers": 4,
+				"tls":     map[string]any{"enabled": true},
+			},
+		}, newServerComponent())
+		require.NoError(t, err)
+		require.Equal(t, ":3030", config.GetOrPanic[string]("server.addr", nil, env))
+		require.Equal(t, time.Second, config.GetOrPanic[time.Duration]("server.timeout", nil, env))
+		require.NoError(t, config.ValidateSchema(env))
+	})
+
+	t.Run("all errors at startup", func(t *testing.T) {
+		_, err := createEnvironment(t, map[string]any{
+			"server": map[string]any{
+				"timeout": "soon",
+				"hosts":   true,
+				"workers": 0,
+				"tls":     map[string]any{"enabled": "maybe"},
+			},
+		}, newServerComponent())
+		require.ErrorIs(t, err, config.ErrInvalidConfig)
+		for _, message := range []string{
+			"server.port: value is required",
+			"server.timeout: expected duration, got string",
+			"server.hosts: expected list, got bool",
+			"server.workers: must be positive",
+			"server.tls.enabled: expected bool, got string",
+		} {
+			require.ErrorContains(t, err, message)
+		}
+	})
+
+	t.Run("processors with dependencies", func(t *testing.T) {
+		componentFactory := component.NewFactory("limits", "1.0.0")
+		componentFactory.SetComponentConfigSchema(func() (string, []*componego.ConfigKey, error) {
+			return "limits", []*componego.ConfigKey{
+				{Key: "max", Processors: []componego.Processor{processors.Multi(processors.ToInt64())}},
+			}, nil
+		})
+		env, err := createEnvironment(t, map[string]any{
+			"limits": map[string]any{"max": "10"},
+		}, componentFactory.Build())
+		require.NoError(t, err)
+		require.NoError(t, config.ValidateSchema(env))
+		_, err = createEnvironment(t, map[string]any{
+			"limits": map[string]any{"max": "many"},
+		}, componentFactory.Build())
+		require.ErrorIs(t, err, config.ErrInvalidConfig)
+		require.ErrorContains(t, err, "limits.max: ")
+	})
+
+	t.Run("invalid schema", func(t *testing.T) {
+		componentFactory := component.NewFactory("unknown-type", "1.0.0")
+		componentFactory.SetComponentConfigSchema(func() (string, []*componego.ConfigKey, error) {
+			return "", []*componego.ConfigKey{{Key: "value", Type: "integer"}}, nil
+		})
+		_, err := config.GetSchema([]componego.Component{componentFactory.Build()})
+		require.ErrorIs(t, err, config.ErrConfigSchema)
+		require.ErrorContains(t, err, `unknown type "integer" of key "value"`)
+		componentFactory = component.NewFactory("duplicate", "1.0.0")
+		componentFactory.SetComponentConfigSchema(func() (string, []*componego.ConfigKey, error) {
+			return "server", []*componego.ConfigKey{{Key: "port"}}, nil
+		})
+		_, err = config.GetSchema([]componego.Component{newServerComponent(), componentFactory.Build()})
+		require.ErrorContains(t, err, `key "server.port" is registered more than once`)
+	})
+
+	t.Run("reload", func(t *testing.T) {
+		appConfig := map[string]any{
+			"server": map[string]any{"port": 80},
+		}
+		env, err := createEnvironment(t, nil, newServerComponent())
+		require.ErrorContains(t, err, "server.port: value is required")
+		require.Nil(t, env)
+		appFactory := application.NewFactory("Config Schema Reload Test Application")
+		appFactory.SetApplicationComponents(func() ([]componego.Component, error) {
+			return []componego.Component{newServerComponent()}, nil
+		})
+		app := newReloadableApplication(appFactory, func(_ componego.ApplicationMode, _ any) (*config.Settings, error) {
+			return config.NewSettings(appConfig), nil
+		})
+		env, cancelEnv, err := driver.New(&driver.Options{
+			AppIO: application.NewIO(nil, io.Discard, io.Discard),
+		}).CreateEnvironment(context.Background(), app, componego.TestMode)
+		require.NoError(t, err)
+		t.Cleanup(func() {
+			require.NoError(t, cancelEnv())
+		})
+		appConfig = map[string]any{
+			"server": map[string]any{"port": "http"},
+		}
+		err = config.Reload(env)
+		require.ErrorIs(t, err, config.ErrInvalidConfig)
+		require.ErrorContains(t, err, "server.port: expected int, got string")
+		require.Equal(t, 80, config.GetOrPanic[int]("server.port", nil, env))
+	})
+
+	t.Run("reference", func(t *testing.T) {
+		schema, err := config.GetSchema([]componego.Component{newServerComponent()})
+		require.NoError(t, err)
+		markdown := config.MarkdownReference(schema)
+		lines := strings.Split(strings.TrimSpace(markdown), "\n")
+		require.Len(t, lines, 8)
+		require.Equal(t, "| `server.addr` | string | `:3030` | no | server | Address \\| host and port |", lines[2])
+		require.Equal(t, "| `server.port` | int |  | yes | server | Port number |", lines[4])
+		data, err := config.JSONReference(schema)
+		require.NoError(t, err)
+		items := make([]map[string]any, 0)
+		require.NoError(t, json.Unmarshal(data, &items))
+		require.Len(t, items, 6)
+		require.Equal(t, map[string]any{
+			"key":       "server.timeout",
+			"type":      "duration",
+			"default":   "1s",
+			"required":  false,
+			"component": "server",
+		}, items[3])
+	})
+}
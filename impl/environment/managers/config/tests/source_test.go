@@ -0,0 +1,135 @@
+/*
+Copyright 2024-present Volodymyr Konstanchuk and contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tests
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/componego/componego"
+	"github.com/componego/componego/impl/environment/managers/config"
+	"github.com/componego/componego/internal/testing/require"
+)
+
+func TestLoadSources(t *testing.T) {
+	writeFile := func(t *testing.T, filename string, content string) string {
+		filename = filepath.Join(t.TempDir(), filename)
+		require.NoError(t, os.WriteFile(filename, []byte(content), 0o600))
+		return filename
+	}
+	// The origins are checked by the provenance tests, so only the values are returned.
+	loadSources := func(t *testing.T, appMode componego.ApplicationMode, options any, sources ...config.Source) (map[string]any, error) {
+		settings, err := config.LoadSources(appMode, options, sources...)
+		if err != nil {
+			return nil, err
+		}
+		return settings.Values, nil
+	}
+
+	t.Run("precedence", func(t *testing.T) {
+		baseFile := writeFile(t, "base.json", `{"server": {"host": "localhost", "port": 80}, "debug": false}`)
+		modeFile := writeFile(t, "test.json", `{"server": {"port": 8080}, "database": {"name": "test"}}`)
+		t.Setenv("COMPONEGO_TEST_SERVER__PORT", "9090")
+		t.Setenv("COMPONEGO_TEST_DATABASE_URL", "postgres://localhost")
+		settings, err := loadSources(t, componego.TestMode, []string{"app", "--config.debug=true", "--other"},
+			config.NewFileSource(baseFile, nil),
+			config.NewModeFileSource(filepath.Join(filepath.Dir(modeFile), "{mode}.json"), nil),
+			config.NewEnvSource("COMPONEGO_TEST_"),
+			config.NewFlagSource("--config."),
+			config.NewMapSource(map[string]any{
+				"database.name": "override",
+			}),
+		)
+		require.NoError(t, err)
+		require.Equal(t, map[string]any{
+			"server": map[string]any{
+				"host": "localhost",
+				"port": "9090",
+			},
+			"database": map[string]any{
+				"name": "override",
+			},
+			"database_url": "postgres://localhost",
+			"debug":        "true",
+		}, settings)
+	})
+
+	t.Run("mode file is optional", func(t *testing.T) {
+		settings, err := loadSources(t, componego.ProductionMode, nil,
+			config.NewModeFileSource(filepath.Join(t.TempDir(), "{mode}.json"), nil),
+		)
+		require.NoError(t, err)
+		require.Len(t, settings, 0)
+	})
+
+	t.Run("base file is required", func(t *testing.T) {
+		_, err := config.LoadSources(componego.ProductionMode, nil,
+			config.NewFileSource(filepath.Join(t.TempDir(), "config.json"), nil),
+		)
+		require.ErrorIs(t, err, config.ErrConfigSource)
+		require.ErrorIs(t, err, os.ErrNotExist)
+	})
+
+	t.Run("custom reader and source", func(t *testing.T) {
+		customErr := errors.New("custom error")
+		filename := writeFile(t, "config.txt", "value")
+		settings, err := loadSources(t, componego.ProductionMode, nil,
+			config.NewFileSource(filename, func(data []byte) (map[string]any, error) {
+				return map[string]any{"key": string(data)}, nil
+			}),
+		)
+		require.NoError(t, err)
+		require.Equal(t, map[string]any{"key": "value"}, settings)
+		_, err = config.LoadSources(componego.ProductionMode, nil,
+			config.SourceFunc(func(_ componego.ApplicationMode, _ any) (map[string]any, error) {
+				return nil, customErr
+			}),
+		)
+		require.ErrorIs(t, err, customErr)
+	})
+
+	t.Run("sources are not modified", func(t *testing.T) {
+		values := map[string]any{
+			"a": map[string]any{"b": 1},
+		}
+		settings, err := loadSources(t, componego.ProductionMode, nil,
+			config.NewMapSource(values),
+			config.NewMapSource(map[string]any{"a.c": 2}),
+		)
+		require.NoError(t, err)
+		require.Equal(t, map[string]any{"a": map[string]any{"b": 1, "c": 2}}, settings)
+		require.Equal(t, map[string]any{"a": map[string]any{"b": 1}}, values)
+	})
+
+	t.Run("escaped keys", func(t *testing.T) {
+		settings, err := loadSources(t, componego.ProductionMode, nil,
+			config.NewMapSource(map[string]any{
+				`a\.b.c`: 1,
+				`d\e`:    2,
+				"f..g":   3,
+			}),
+		)
+		require.NoError(t, err)
+		require.Equal(t, map[string]any{
+			"a.b":  map[string]any{"c": 1},
+			`d\e`:  2,
+			"f..g": 3,
+		}, settings)
+	})
+}
@@ -0,0 +1,140 @@
+/*
+Copyright 2024-present Volodymyr Konstanchuk and contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"reflect"
+	"sort"
+	"strconv"
+
+	"github.com/componego/componego"
+	"github.com/componego/componego/libs/xerrors"
+)
+
+var (
+	ErrNoChildKeys = ErrConfigGet.WithMessage("config value has no child keys", "E0383")
+)
+
+// TreeProvider is an interface that describes a config provider that can enumerate configuration keys.
+// Providers that do not implement this interface are supported by the functions Keys, Has and Sub
+// through method ConfigValue.
+type TreeProvider interface {
+	componego.ConfigProvider
+	// ConfigKeys returns the sorted child keys of the object or the indexes of the list by the key.
+	// The keys are escaped, so they can be joined with the prefix.
+	ConfigKeys(prefix string) ([]string, error)
+	// ConfigHas returns true if the configuration contains a value by the key.
+	ConfigHas(configKey string) bool
+}
+
+// Keys returns the sorted child keys of the object or the indexes of the list by the key.
+// For example, the names of all databases are available by the prefix "databases".
+// An empty prefix means the whole configuration.
+func Keys(configProvider componego.ConfigProvider, prefix string) ([]string, error) {
+	if treeProvider, ok := configProvider.(TreeProvider); ok {
+		return treeProvider.ConfigKeys(prefix)
+	}
+	value, err := configProvider.ConfigValue(prefix, nil)
+	if err != nil {
+		return nil, err
+	}
+	return getChildKeys(prefix, value)
+}
+
+// Has returns true if the configuration contains a value by the key.
+func Has(configProvider componego.ConfigProvider, configKey string) bool {
+	if treeProvider, ok := configProvider.(TreeProvider); ok {
+		return treeProvider.ConfigHas(configKey)
+	}
+	_, err := configProvider.ConfigValue(configKey, nil)
+	return err == nil
+}
+
+// Sub returns a read-only view of the configuration in which all keys are relative to the prefix.
+// Unlike NewScope, the prefix must exist and contain an object or a list.
+func Sub(configProvider componego.ConfigProvider, prefix string) (TreeProvider, error) {
+	if _, err := Keys(configProvider, prefix); err != nil {
+		return nil, err
+	}
+	return &scope{
+		configProvider: configProvider,
+		prefix:         prefix,
+	}, nil
+}
+
+// ConfigKeys belongs to interface TreeProvider.
+func (m *manager) ConfigKeys(prefix string) ([]string, error) {
+	// An empty prefix means the whole configuration, so the value is not requested through ConfigValue.
+	value, ok := m.extractValue(prefix)
+	if !ok {
+		// The error of ConfigValue describes why the value is not found.
+		_, err := m.ConfigValue(prefix, nil)
+		return nil, err
+	}
+	return getChildKeys(prefix, value)
+}
+
+// ConfigHas belongs to interface TreeProvider.
+func (m *manager) ConfigHas(configKey string) bool {
+	_, ok := m.extractValue(configKey)
+	return ok
+}
+
+// getChildKeys returns the escaped keys of the object or the indexes of the list.
+func getChildKeys(prefix string, value any) ([]string, error) {
+	switch castedValue := value.(type) {
+	case map[string]any:
+		keys := make([]string, 0, len(castedValue))
+		for key := range castedValue {
+			keys = append(keys, EscapeKey(key))
+		}
+		sort.Strings(keys)
+		return keys, nil
+	case []any:
+		return getIndexes(len(castedValue)), nil
+	}
+	reflectValue := reflect.ValueOf(value)
+	switch reflectValue.Kind() {
+	case reflect.Map:
+		if reflectValue.Type().Key().Kind() == reflect.String {
+			keys := make([]string, 0, reflectValue.Len())
+			for _, key := range reflectValue.MapKeys() {
+				keys = append(keys, EscapeKey(key.String()))
+			}
+			sort.Strings(keys)
+			return keys, nil
+		}
+	case reflect.Slice, reflect.Array:
+		return getIndexes(reflectValue.Len()), nil
+	}
+	return nil, ErrNoChildKeys.WithOptions("E0384",
+		xerrors.NewOption("componego:config:key", prefix),
+	)
+}
+
+func getIndexes(length int) []string {
+	indexes := make([]string, length)
+	for i := range indexes {
+		indexes[i] = strconv.Itoa(i)
+	}
+	return indexes
+}
+
+var (
+	_ TreeProvider = (*manager)(nil)
+	_ TreeProvider = (*scope)(nil)
+)
@@ -0,0 +1,119 @@
+/*
+Copyright 2024-present Volodymyr Konstanchuk and contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/componego/componego"
+	"github.com/componego/componego/internal/developer"
+)
+
+// WatchOptions are the options of the configuration watcher.
+type WatchOptions struct {
+	// Filenames are files that are checked for changes.
+	// The configuration is reloaded if the modification time or the size of any file changes.
+	Filenames []string
+	// Interval is the interval between file checks. The default value is one second.
+	Interval time.Duration
+	// Signals are signals that reload the configuration, for example, syscall.SIGHUP.
+	Signals []os.Signal
+	// OnError is called when reloading fails. By default, errors are written to the error output of the application.
+	OnError func(err error)
+}
+
+type fileState struct {
+	modTime time.Time
+	size    int64
+}
+
+// Watch reloads the configuration when files change or signals are received.
+// The function blocks until the context is cancelled, so it can be called inside componego.ComponentRun.
+func Watch(ctx context.Context, env componego.Environment, options *WatchOptions) error {
+	if _, err := getManager(env); err != nil {
+		return err
+	}
+	if options == nil {
+		options = &WatchOptions{}
+	}
+	onError := options.OnError
+	if onError == nil {
+		onError = func(err error) {
+			developer.Warning(env.ApplicationIO().ErrorOutputWriter(), "Failed to reload the configuration:", err)
+		}
+	}
+	var signals chan os.Signal
+	if len(options.Signals) > 0 {
+		signals = make(chan os.Signal, 1)
+		signal.Notify(signals, options.Signals...)
+		defer signal.Stop(signals)
+	}
+	var ticker <-chan time.Time
+	states := make([]fileState, len(options.Filenames))
+	if len(options.Filenames) > 0 {
+		interval := options.Interval
+		if interval <= 0 {
+			interval = time.Second
+		}
+		for i, filename := range options.Filenames {
+			states[i] = getFileState(filename)
+		}
+		timeTicker := time.NewTicker(interval)
+		defer timeTicker.Stop()
+		ticker = timeTicker.C
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-signals:
+		case <-ticker:
+			changed := false
+			for i, filename := range options.Filenames {
+				if state := getFileState(filename); !state.equal(states[i]) {
+					states[i] = state
+					changed = true
+				}
+			}
+			if !changed {
+				continue
+			}
+		}
+		if err := Reload(env); err != nil {
+			onError(err)
+		}
+	}
+}
+
+func (f fileState) equal(other fileState) bool {
+	return f.size == other.size && f.modTime.Equal(other.modTime)
+}
+
+// getFileState returns an empty state if the file does not exist.
+func getFileState(filename string) fileState {
+	info, err := os.Stat(filename)
+	if err != nil {
+		return fileState{}
+	}
+	return fileState{
+		modTime: info.ModTime(),
+		size:    info.Size(),
+	}
+}
@@ -24,6 +24,7 @@ import (
 
 	"github.com/componego/componego"
 	"github.com/componego/componego/impl/environment/managers/dependency/container"
+	"github.com/componego/componego/impl/events"
 	"github.com/componego/componego/internal/utils"
 	"github.com/componego/componego/libs/xerrors"
 )
@@ -35,13 +36,44 @@ var (
 	ErrNotFunction         = ErrDependencyManager.WithMessage("argument is not a function and cannot be used as a constructor for dependency injection", "E0513")
 	ErrVariadicFunction    = ErrDependencyManager.WithMessage("function has a variable number of arguments and cannot be used as a constructor for dependency injection", "E0514")
 	ErrNotAllowedTarget    = ErrDependencyManager.WithMessage("target is not allowed for dependency injection", "E0515")
+	ErrProtectedDependency = ErrExtractDependencies.WithMessage("default dependency cannot be overridden", "E0526")
 )
 
+// DefaultDependency describes a dependency that will be present in any application.
+type DefaultDependency struct {
+	// Dependency is an object or a function that returns objects.
+	Dependency componego.Dependency
+	// Overridable specifies whether components and the application can replace this dependency.
+	// Overridable dependencies are added before all other dependencies, and protected dependencies are added after them.
+	Overridable bool
+}
+
+// TypeProvider is an interface that describes a dependency invoker that knows the dependencies of each component.
+type TypeProvider interface {
+	componego.DependencyInvoker
+	// ComponentTypes returns the types provided by the dependencies of the component with the identifier.
+	ComponentTypes(identifier string) []reflect.Type
+	// TypeComponent returns the component whose dependency provides the type.
+	// The second value is false if the type is provided by the application or by a protected default dependency.
+	TypeComponent(reflectType reflect.Type) (componego.Component, bool)
+}
+
 type manager struct {
-	container container.Container
+	container      container.Container
+	componentTypes map[string][]reflect.Type
+	typeComponents map[reflect.Type]componego.Component
 }
 
 func NewManager() (componego.DependencyInvoker, func(container.Container) error) {
+	m := &manager{}
+	return m, func(container container.Container) error {
+		return m.initialize(container, nil)
+	}
+}
+
+// NewManagerWithDependencies returns the dependency manager whose initializer also receives the collected dependencies.
+// The manager remembers which types the dependencies of each component provide.
+func NewManagerWithDependencies() (componego.DependencyInvoker, func(container.Container, *Dependencies) error) {
 	m := &manager{}
 	return m, m.initialize
 }
@@ -152,16 +184,59 @@ func (m *manager) PopulateFields(target any) error {
 	return nil
 }
 
-func (m *manager) initialize(container container.Container) error {
+// ComponentTypes belongs to interface TypeProvider.
+func (m *manager) ComponentTypes(identifier string) []reflect.Type {
+	types := m.componentTypes[identifier]
+	if len(types) == 0 {
+		return nil
+	}
+	// Copy the slice to avoid modification from outside the manager.
+	return utils.Copy(types)
+}
+
+// TypeComponent belongs to interface TypeProvider.
+func (m *manager) TypeComponent(reflectType reflect.Type) (componego.Component, bool) {
+	component, ok := m.typeComponents[reflectType]
+	return component, ok
+}
+
+func (m *manager) initialize(container container.Container, dependencies *Dependencies) error {
 	m.container = container
+	if dependencies != nil {
+		m.componentTypes = dependencies.componentTypes
+		m.typeComponents = dependencies.typeComponents
+	}
 	return nil
 }
 
+// Dependencies contains the list of dependencies and the types that the dependencies of each component provide.
+type Dependencies struct {
+	// List is a raw list of dependencies without any transformations.
+	List           []componego.Dependency
+	componentTypes map[string][]reflect.Type
+	// typeComponents contains the components whose dependencies are used for each type.
+	typeComponents map[reflect.Type]componego.Component
+}
+
 // ExtractDependencies returns a list of dependencies from the application and components.
 // This is a raw list without any transformations.
-func ExtractDependencies(env componego.Environment) ([]componego.Dependency, error) {
+// Additional default dependencies are added to the dependencies that are present in any application.
+func ExtractDependencies(env componego.Environment, defaultDependencies ...DefaultDependency) ([]componego.Dependency, error) {
+	dependencies, err := CollectDependencies(env, defaultDependencies...)
+	if err != nil {
+		return nil, err
+	}
+	return dependencies.List, nil
+}
+
+// CollectDependencies returns the same list of dependencies as ExtractDependencies
+// and remembers the types that the dependencies of each component provide.
+// The result is passed to the initializer of NewManagerWithDependencies.
+func CollectDependencies(env componego.Environment, defaultDependencies ...DefaultDependency) (*Dependencies, error) {
 	components := env.Components()
 	allDependencies := make([][]componego.Dependency, 0, len(components)+1)
+	componentTypes := make(map[string][]reflect.Type, len(components))
+	typeComponents := make(map[reflect.Type]componego.Component)
 	countDependencies := 0
 	for _, component := range components {
 		if component, ok := component.(componego.ComponentDependencies); !ok {
@@ -173,6 +248,14 @@ func ExtractDependencies(env componego.Environment) ([]componego.Dependency, err
 		} else if len(dependencies) > 0 {
 			allDependencies = append(allDependencies, dependencies)
 			countDependencies += len(dependencies)
+			for _, dependency := range dependencies {
+				providedTypes := GetProvidedTypes(dependency)
+				componentTypes[component.ComponentIdentifier()] = append(componentTypes[component.ComponentIdentifier()], providedTypes...)
+				// Dependencies of the last component override dependencies of previous components.
+				for _, providedType := range providedTypes {
+					typeComponents[providedType] = component
+				}
+			}
 		}
 	}
 	if app, ok := env.Application().(componego.ApplicationDependencies); ok {
@@ -181,16 +264,48 @@ func ExtractDependencies(env componego.Environment) ([]componego.Dependency, err
 		} else if len(dependencies) > 0 {
 			allDependencies = append(allDependencies, dependencies)
 			countDependencies += len(dependencies)
+			// Dependencies of the application override dependencies of components.
+			deleteProvidedTypes(typeComponents, dependencies)
 		}
 	}
-	defaultDependencies := getDefaultDependencies(env)
-	dependencies := make([]componego.Dependency, 0, countDependencies+len(defaultDependencies))
+	overridableDependencies := make([]componego.Dependency, 0, len(defaultDependencies))
+	protectedDependencies := make([]componego.Dependency, 0, len(defaultDependencies)+8)
+	for _, defaultDependency := range defaultDependencies {
+		if defaultDependency.Overridable {
+			overridableDependencies = append(overridableDependencies, defaultDependency.Dependency)
+		} else {
+			protectedDependencies = append(protectedDependencies, defaultDependency.Dependency)
+		}
+	}
+	if err := checkProtectedDependencies(allDependencies, protectedDependencies); err != nil {
+		return nil, err
+	}
+	// Dependencies that are present in any application are also protected,
+	// but they silently take precedence over dependencies that provide the same types.
+	protectedDependencies = append(protectedDependencies, getDefaultDependencies(env)...)
+	deleteProvidedTypes(typeComponents, protectedDependencies)
+	dependencies := make([]componego.Dependency, 0, countDependencies+len(overridableDependencies)+len(protectedDependencies))
+	// Overridable dependencies are added at the beginning, so any component or application can replace them.
+	dependencies = append(dependencies, overridableDependencies...)
 	for _, list := range allDependencies {
 		dependencies = append(dependencies, list...)
 	}
-	// Adds dependencies that will be present in any application and cannot be overwritten (because they are added at the end).
-	dependencies = append(dependencies, defaultDependencies...)
-	return dependencies, nil
+	// Adds dependencies that cannot be overwritten (because they are added at the end).
+	dependencies = append(dependencies, protectedDependencies...)
+	return &Dependencies{
+		List:           dependencies,
+		componentTypes: componentTypes,
+		typeComponents: typeComponents,
+	}, nil
+}
+
+// deleteProvidedTypes removes the types that the dependencies provide from the components of types.
+func deleteProvidedTypes(typeComponents map[reflect.Type]componego.Component, dependencies []componego.Dependency) {
+	for _, dependency := range dependencies {
+		for _, providedType := range GetProvidedTypes(dependency) {
+			delete(typeComponents, providedType)
+		}
+	}
 }
 
 // getDefaultDependencies returns dependencies that will be present in any application.
@@ -207,5 +322,55 @@ func getDefaultDependencies(env componego.Environment) []componego.Dependency {
 		env.ApplicationIO,
 		env.ConfigProvider,
 		env.DependencyInvoker,
+		// Each environment has its own event bus.
+		events.NewBus,
+	}
+}
+
+// checkProtectedDependencies returns an error if any dependency provides the same type as a protected default dependency.
+// Otherwise, such a dependency would be silently replaced.
+func checkProtectedDependencies(allDependencies [][]componego.Dependency, protectedDependencies []componego.Dependency) error {
+	protectedTypes := make(map[reflect.Type]struct{}, len(protectedDependencies))
+	for _, dependency := range protectedDependencies {
+		for _, providedType := range GetProvidedTypes(dependency) {
+			protectedTypes[providedType] = struct{}{}
+		}
+	}
+	for _, list := range allDependencies {
+		for _, dependency := range list {
+			for _, providedType := range GetProvidedTypes(dependency) {
+				if _, ok := protectedTypes[providedType]; !ok {
+					continue
+				}
+				return ErrProtectedDependency.WithOptions("E0527",
+					xerrors.NewOption("componego:dependency:providedType", providedType),
+				)
+			}
+		}
 	}
+	return nil
+}
+
+// GetProvidedTypes returns the types that the dependency provides.
+// Invalid dependencies are ignored here because they are validated inside the container.
+func GetProvidedTypes(dependency componego.Dependency) []reflect.Type {
+	if dependency == nil {
+		return nil
+	}
+	reflectType := reflect.TypeOf(dependency)
+	switch reflectType.Kind() {
+	case reflect.Func:
+		numOut := reflectType.NumOut()
+		if numOut > 0 && utils.IsErrorType(reflectType.Out(numOut-1)) {
+			numOut--
+		}
+		result := make([]reflect.Type, numOut)
+		for i := 0; i < numOut; i++ {
+			result[i] = reflectType.Out(i)
+		}
+		return result
+	case reflect.Pointer:
+		return []reflect.Type{reflectType}
+	}
+	return nil
 }
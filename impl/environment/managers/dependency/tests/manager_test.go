@@ -17,11 +17,127 @@ limitations under the License.
 package tests
 
 import (
+	"context"
+	"io"
+	"reflect"
 	"testing"
 
+	"github.com/componego/componego"
+	"github.com/componego/componego/impl/application"
+	"github.com/componego/componego/impl/driver"
+	"github.com/componego/componego/impl/environment/managers/component"
 	"github.com/componego/componego/impl/environment/managers/dependency"
+	"github.com/componego/componego/internal/testing/require"
+	"github.com/componego/componego/internal/testing/types"
 )
 
 func TestDependencyManager(t *testing.T) {
 	DependencyManagerTester[*testing.T](t, dependency.NewManager)
 }
+
+func TestExtractDependencies(t *testing.T) {
+	defaultValue := &types.AStruct{
+		Value: 1,
+	}
+	appValue := &types.AStruct{
+		Value: 2,
+	}
+	createEnvironment := func(overridable bool, appDependencies ...componego.Dependency) (componego.Environment, error) {
+		appFactory := application.NewFactory("Test Application")
+		appFactory.SetApplicationDependencies(func() ([]componego.Dependency, error) {
+			return appDependencies, nil
+		})
+		d := driver.New(&driver.Options{
+			AppIO: application.NewIO(nil, io.Discard, io.Discard),
+			DefaultDependencies: []dependency.DefaultDependency{
+				{
+					Dependency:  defaultValue,
+					Overridable: overridable,
+				},
+			},
+		})
+		env, cancelEnv, err := d.CreateEnvironment(context.Background(), appFactory.Build(), componego.TestMode)
+		if err == nil {
+			t.Cleanup(func() {
+				require.NoError(t, cancelEnv())
+			})
+		}
+		return env, err
+	}
+
+	t.Run("default dependency is available", func(t *testing.T) {
+		for _, overridable := range []bool{true, false} {
+			env, err := createEnvironment(overridable)
+			require.NoError(t, err)
+			value, err := dependency.Get[*types.AStruct](env)
+			require.NoError(t, err)
+			require.Same(t, defaultValue, value)
+		}
+	})
+
+	t.Run("overridable dependency is replaced", func(t *testing.T) {
+		env, err := createEnvironment(true, appValue)
+		require.NoError(t, err)
+		value, err := dependency.Get[*types.AStruct](env)
+		require.NoError(t, err)
+		require.Same(t, appValue, value)
+	})
+
+	t.Run("protected dependency cannot be replaced", func(t *testing.T) {
+		_, err := createEnvironment(false, appValue)
+		require.ErrorIs(t, err, dependency.ErrProtectedDependency)
+		_, err = createEnvironment(false, func() (*types.BStruct, *types.AStruct, error) {
+			return nil, appValue, nil
+		})
+		require.ErrorIs(t, err, dependency.ErrProtectedDependency)
+	})
+
+	t.Run("built-in dependency is not replaced", func(t *testing.T) {
+		env, err := createEnvironment(true, func() componego.ApplicationIO {
+			return nil
+		})
+		require.NoError(t, err)
+		value, err := dependency.Get[componego.ApplicationIO](env)
+		require.NoError(t, err)
+		require.Same(t, env.ApplicationIO(), value)
+	})
+}
+
+func TestTypeComponent(t *testing.T) {
+	newComponent := func(identifier string, dependencies ...componego.Dependency) componego.Component {
+		componentFactory := component.NewFactory(identifier, "0.0.1")
+		componentFactory.SetComponentDependencies(func() ([]componego.Dependency, error) {
+			return dependencies, nil
+		})
+		return componentFactory.Build()
+	}
+	component1 := newComponent("component1", &types.AStruct{}, &types.BStruct{})
+	component2 := newComponent("component2", &types.AStruct{})
+	appFactory := application.NewFactory("Test Application")
+	appFactory.SetApplicationComponents(func() ([]componego.Component, error) {
+		return []componego.Component{component1, component2}, nil
+	})
+	appFactory.SetApplicationDependencies(func() ([]componego.Dependency, error) {
+		return []componego.Dependency{&types.BStruct{}}, nil
+	})
+	d := driver.New(&driver.Options{
+		AppIO: application.NewIO(nil, io.Discard, io.Discard),
+	})
+	env, cancelEnv, err := d.CreateEnvironment(context.Background(), appFactory.Build(), componego.TestMode)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, cancelEnv())
+	})
+	typeProvider, ok := env.DependencyInvoker().(dependency.TypeProvider)
+	require.True(t, ok)
+	// The dependency of the last component is used.
+	owner, ok := typeProvider.TypeComponent(reflect.TypeOf(&types.AStruct{}))
+	require.True(t, ok)
+	require.Same(t, component2, owner)
+	// The dependency of the application is used.
+	_, ok = typeProvider.TypeComponent(reflect.TypeOf(&types.BStruct{}))
+	require.False(t, ok)
+	// The environment is a built-in dependency.
+	_, ok = typeProvider.TypeComponent(reflect.TypeOf((*componego.Environment)(nil)).Elem())
+	require.False(t, ok)
+}
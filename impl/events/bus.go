@@ -0,0 +1,208 @@
+/*
+Copyright 2024-present Volodymyr Konstanchuk and contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package events
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+
+	"github.com/componego/componego"
+	"github.com/componego/componego/libs/debug"
+	"github.com/componego/componego/libs/xerrors"
+)
+
+var (
+	ErrEventBus        = xerrors.New("event bus error", "E0710")
+	ErrBusClosed       = ErrEventBus.WithMessage("event bus is closed", "E0711")
+	ErrSubscriberPanic = ErrEventBus.WithMessage("event subscriber panicked", "E0712")
+)
+
+// Handler is a function that receives events.
+type Handler = func(ctx context.Context, event any) error
+
+// Bus is an interface that describes an in-process event bus.
+// Events are matched to subscribers by the exact type of the event.
+// Use generic functions Subscribe, Publish and PublishAsync to work with typed events.
+type Bus interface {
+	// Subscribe adds a handler for events of the type.
+	// The subscription is removed when the owner component stops. The owner can be nil.
+	Subscribe(eventType reflect.Type, owner componego.Component, handler Handler) Subscription
+	// Publish delivers the event to all subscribers in the current goroutine.
+	// All subscribers receive the event even if some of them return an error or panic.
+	Publish(ctx context.Context, eventType reflect.Type, event any) error
+	// PublishAsync delivers the event to all subscribers in a separate goroutine.
+	// The returned channel receives the result of the delivery. It can be ignored.
+	PublishAsync(ctx context.Context, eventType reflect.Type, event any) <-chan error
+	// UnsubscribeComponent removes all subscriptions that belong to the component.
+	UnsubscribeComponent(component componego.Component)
+}
+
+// Subscription is an interface that describes a subscription to events.
+type Subscription interface {
+	// Unsubscribe removes the subscription. It can be called multiple times.
+	Unsubscribe()
+}
+
+type bus struct {
+	mutex         sync.RWMutex
+	subscriptions map[reflect.Type][]*subscription
+	closed        bool
+	waitGroup     sync.WaitGroup
+}
+
+type subscription struct {
+	bus       *bus
+	eventType reflect.Type
+	owner     componego.Component
+	handler   Handler
+}
+
+// NewBus creates a new event bus.
+func NewBus() Bus {
+	return &bus{
+		subscriptions: make(map[reflect.Type][]*subscription),
+	}
+}
+
+// Subscribe belongs to interface Bus.
+func (b *bus) Subscribe(eventType reflect.Type, owner componego.Component, handler Handler) Subscription {
+	s := &subscription{
+		bus:       b,
+		eventType: eventType,
+		owner:     owner,
+		handler:   handler,
+	}
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	// A new slice is created so that deliveries in progress keep their own list of subscribers.
+	subscriptions := b.subscriptions[eventType]
+	b.subscriptions[eventType] = append(subscriptions[:len(subscriptions):len(subscriptions)], s)
+	return s
+}
+
+// Publish belongs to interface Bus.
+func (b *bus) Publish(ctx context.Context, eventType reflect.Type, event any) error {
+	b.mutex.RLock()
+	if b.closed {
+		b.mutex.RUnlock()
+		return ErrBusClosed.WithOptions("E0713",
+			xerrors.NewOption("componego:events:type", eventType),
+		)
+	}
+	subscriptions := b.subscriptions[eventType]
+	b.mutex.RUnlock()
+	return deliver(ctx, subscriptions, event)
+}
+
+// PublishAsync belongs to interface Bus.
+func (b *bus) PublishAsync(ctx context.Context, eventType reflect.Type, event any) <-chan error {
+	result := make(chan error, 1)
+	b.mutex.RLock()
+	defer b.mutex.RUnlock()
+	if b.closed {
+		result <- ErrBusClosed.WithOptions("E0714",
+			xerrors.NewOption("componego:events:type", eventType),
+		)
+		return result
+	}
+	subscriptions := b.subscriptions[eventType]
+	b.waitGroup.Add(1)
+	go func() {
+		defer b.waitGroup.Done()
+		result <- deliver(ctx, subscriptions, event)
+	}()
+	return result
+}
+
+// UnsubscribeComponent belongs to interface Bus.
+func (b *bus) UnsubscribeComponent(component componego.Component) {
+	identifier := component.ComponentIdentifier()
+	b.unsubscribe(func(s *subscription) bool {
+		return s.owner != nil && s.owner.ComponentIdentifier() == identifier
+	})
+}
+
+// Close waits for all asynchronous deliveries and rejects new events.
+// This method is called by the dependency container when the environment is stopped.
+func (b *bus) Close() error {
+	b.mutex.Lock()
+	b.closed = true
+	b.mutex.Unlock()
+	b.waitGroup.Wait()
+	return nil
+}
+
+func (b *bus) unsubscribe(match func(s *subscription) bool) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	for eventType, subscriptions := range b.subscriptions {
+		filtered := make([]*subscription, 0, len(subscriptions))
+		for _, s := range subscriptions {
+			if !match(s) {
+				filtered = append(filtered, s)
+			}
+		}
+		if len(filtered) == 0 {
+			delete(b.subscriptions, eventType)
+		} else if len(filtered) != len(subscriptions) {
+			b.subscriptions[eventType] = filtered
+		}
+	}
+}
+
+// Unsubscribe belongs to interface Subscription.
+func (s *subscription) Unsubscribe() {
+	s.bus.unsubscribe(func(item *subscription) bool {
+		return item == s
+	})
+}
+
+// deliver calls subscribers in the order of subscription.
+func deliver(ctx context.Context, subscriptions []*subscription, event any) error {
+	errs := make([]error, 0)
+	for _, s := range subscriptions {
+		if err := s.call(ctx, event); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// call calls the subscriber. A panic in one subscriber does not affect other subscribers.
+func (s *subscription) call(ctx context.Context, event any) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			errOptions := []xerrors.Option{
+				xerrors.NewOption("componego:events:type", s.eventType),
+				xerrors.NewOption("componego:events:panic:recover", r),
+				xerrors.NewOption("componego:events:panic:stack", debug.GetStackTrace(1)),
+			}
+			if s.owner != nil {
+				errOptions = append(errOptions, xerrors.NewOption("componego:events:component", s.owner))
+			}
+			if recoverErr, ok := r.(error); ok {
+				err = ErrSubscriberPanic.WithError(recoverErr, "E0715", errOptions...)
+			} else {
+				err = ErrSubscriberPanic.WithMessage(fmt.Sprint(r), "E0716", errOptions...)
+			}
+		}
+	}()
+	return s.handler(ctx, event)
+}
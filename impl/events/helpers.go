@@ -0,0 +1,54 @@
+/*
+Copyright 2024-present Volodymyr Konstanchuk and contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package events
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/componego/componego"
+)
+
+// Subscribe adds a handler for events of type T that does not belong to any component.
+func Subscribe[T any](bus Bus, handler func(ctx context.Context, event T) error) Subscription {
+	return SubscribeFor[T](bus, nil, handler)
+}
+
+// SubscribeFor adds a handler for events of type T.
+// The subscription is removed automatically when the component stops.
+func SubscribeFor[T any](bus Bus, component componego.Component, handler func(ctx context.Context, event T) error) Subscription {
+	return bus.Subscribe(getEventType[T](), component, func(ctx context.Context, event any) error {
+		// The event can be nil if T is an interface.
+		value, _ := event.(T)
+		return handler(ctx, value)
+	})
+}
+
+// Publish delivers the event to all subscribers of type T synchronously.
+// Errors and panics of subscribers are joined and returned.
+func Publish[T any](ctx context.Context, bus Bus, event T) error {
+	return bus.Publish(ctx, getEventType[T](), event)
+}
+
+// PublishAsync delivers the event to all subscribers of type T in a separate goroutine.
+func PublishAsync[T any](ctx context.Context, bus Bus, event T) <-chan error {
+	return bus.PublishAsync(ctx, getEventType[T](), event)
+}
+
+func getEventType[T any]() reflect.Type {
+	return reflect.TypeOf((*T)(nil)).Elem()
+}
@@ -0,0 +1,183 @@
+/*
+Copyright 2024-present Volodymyr Konstanchuk and contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tests
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/componego/componego"
+	"github.com/componego/componego/impl/application"
+	"github.com/componego/componego/impl/driver"
+	"github.com/componego/componego/impl/environment/managers/component"
+	"github.com/componego/componego/impl/environment/managers/dependency"
+	"github.com/componego/componego/impl/events"
+	"github.com/componego/componego/internal/testing/require"
+	"github.com/componego/componego/tests/runner"
+)
+
+type userCreated struct {
+	name string
+}
+
+type serverStarted struct{}
+
+func TestBus(t *testing.T) {
+	customErr := errors.New("custom error")
+
+	t.Run("sync delivery", func(t *testing.T) {
+		bus := events.NewBus()
+		received := make([]string, 0)
+		events.Subscribe(bus, func(_ context.Context, event userCreated) error {
+			received = append(received, "first "+event.name)
+			return nil
+		})
+		events.Subscribe(bus, func(_ context.Context, event userCreated) error {
+			received = append(received, "second "+event.name)
+			return nil
+		})
+		events.Subscribe(bus, func(_ context.Context, _ serverStarted) error {
+			received = append(received, "server")
+			return nil
+		})
+		require.NoError(t, events.Publish(context.Background(), bus, userCreated{name: "user"}))
+		require.Equal(t, []string{"first user", "second user"}, received)
+		require.NoError(t, events.Publish(context.Background(), bus, 123))
+		require.Len(t, received, 2)
+	})
+
+	t.Run("panic isolation", func(t *testing.T) {
+		bus := events.NewBus()
+		called := false
+		events.Subscribe(bus, func(_ context.Context, _ userCreated) error {
+			panic("panic inside subscriber")
+		})
+		events.Subscribe(bus, func(_ context.Context, _ userCreated) error {
+			return customErr
+		})
+		events.Subscribe(bus, func(_ context.Context, _ userCreated) error {
+			called = true
+			return nil
+		})
+		err := events.Publish(context.Background(), bus, userCreated{})
+		require.ErrorIs(t, err, events.ErrSubscriberPanic)
+		require.ErrorIs(t, err, customErr)
+		require.True(t, called)
+	})
+
+	t.Run("async delivery", func(t *testing.T) {
+		bus := events.NewBus()
+		mutex := sync.Mutex{}
+		received := make([]string, 0)
+		events.Subscribe(bus, func(_ context.Context, event userCreated) error {
+			mutex.Lock()
+			defer mutex.Unlock()
+			received = append(received, event.name)
+			return customErr
+		})
+		require.ErrorIs(t, <-events.PublishAsync(context.Background(), bus, userCreated{name: "user 1"}), customErr)
+		events.PublishAsync(context.Background(), bus, userCreated{name: "user 2"})
+		// Closing the bus waits for all deliveries.
+		require.NoError(t, bus.(interface{ Close() error }).Close())
+		require.Equal(t, []string{"user 1", "user 2"}, received)
+		require.ErrorIs(t, events.Publish(context.Background(), bus, userCreated{}), events.ErrBusClosed)
+		require.ErrorIs(t, <-events.PublishAsync(context.Background(), bus, userCreated{}), events.ErrBusClosed)
+	})
+
+	t.Run("unsubscribe", func(t *testing.T) {
+		bus := events.NewBus()
+		counter := 0
+		subscription := events.Subscribe(bus, func(_ context.Context, _ userCreated) error {
+			counter++
+			return nil
+		})
+		owner := component.NewFactory("component", "0.0.1").Build()
+		events.SubscribeFor(bus, owner, func(_ context.Context, _ userCreated) error {
+			counter += 10
+			return nil
+		})
+		require.NoError(t, events.Publish(context.Background(), bus, userCreated{}))
+		require.Equal(t, 11, counter)
+		subscription.Unsubscribe()
+		subscription.Unsubscribe()
+		require.NoError(t, events.Publish(context.Background(), bus, userCreated{}))
+		require.Equal(t, 21, counter)
+		bus.UnsubscribeComponent(owner)
+		require.NoError(t, events.Publish(context.Background(), bus, userCreated{}))
+		require.Equal(t, 21, counter)
+	})
+}
+
+func TestEnvironmentBus(t *testing.T) {
+	t.Run("default dependency", func(t *testing.T) {
+		env, cancelEnv := runner.CreateTestEnvironment(t, application.NewFactory("Events Test Application").Build(), nil)
+		t.Cleanup(cancelEnv)
+		bus1, err := dependency.Get[events.Bus](env)
+		require.NoError(t, err)
+		bus2, err := dependency.Get[events.Bus](env)
+		require.NoError(t, err)
+		require.Same(t, bus1, bus2)
+	})
+
+	t.Run("subscriptions are removed in stop order", func(t *testing.T) {
+		received := make([]string, 0)
+		newComponent := func(identifier string, components ...componego.Component) componego.Component {
+			var current componego.Component
+			factory := component.NewFactory(identifier, "0.0.1")
+			factory.SetComponentComponents(func() ([]componego.Component, error) {
+				return components, nil
+			})
+			factory.SetComponentInit(func(env componego.Environment) error {
+				bus := dependency.GetOrPanic[events.Bus](env)
+				events.SubscribeFor(bus, current, func(_ context.Context, event userCreated) error {
+					received = append(received, identifier+" <- "+event.name)
+					return nil
+				})
+				return nil
+			})
+			factory.SetComponentStop(func(env componego.Environment, prevErr error) error {
+				bus := dependency.GetOrPanic[events.Bus](env)
+				return errors.Join(prevErr, events.Publish(context.Background(), bus, userCreated{name: identifier}))
+			})
+			current = factory.Build()
+			return current
+		}
+		appFactory := application.NewFactory("Events Test Application")
+		appFactory.SetApplicationComponents(func() ([]componego.Component, error) {
+			return []componego.Component{
+				newComponent("component 2", newComponent("component 1")),
+			}, nil
+		})
+		appFactory.SetApplicationAction(func(_ componego.Environment, _ any) (int, error) {
+			return componego.SuccessExitCode, nil
+		})
+		d := driver.New(&driver.Options{
+			AppIO: application.NewIO(nil, &bytes.Buffer{}, &bytes.Buffer{}),
+		})
+		exitCode, err := d.RunApplication(context.Background(), appFactory.Build(), componego.TestMode)
+		require.NoError(t, err)
+		require.Equal(t, componego.SuccessExitCode, exitCode)
+		require.Equal(t, []string{
+			"component 1 <- component 2",
+			"component 2 <- component 2",
+			"component 1 <- component 1",
+		}, received)
+	})
+}
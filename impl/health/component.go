@@ -0,0 +1,56 @@
+/*
+Copyright 2024-present Volodymyr Konstanchuk and contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package health
+
+import (
+	"github.com/componego/componego"
+)
+
+// Component provides the health registry as a dependency.
+type Component struct {
+	options *Options
+}
+
+func NewComponent(options *Options) *Component {
+	return &Component{
+		options: options,
+	}
+}
+
+// ComponentIdentifier belongs to interface componego.Component.
+func (c *Component) ComponentIdentifier() string {
+	return "componego:health"
+}
+
+// ComponentVersion belongs to interface componego.Component.
+func (c *Component) ComponentVersion() string {
+	return "0.0.1"
+}
+
+// ComponentDependencies belongs to interface componego.ComponentDependencies.
+func (c *Component) ComponentDependencies() ([]componego.Dependency, error) {
+	return []componego.Dependency{
+		func(env componego.Environment) Registry {
+			return NewRegistry(env, c.options)
+		},
+	}, nil
+}
+
+var (
+	_ componego.Component             = (*Component)(nil)
+	_ componego.ComponentDependencies = (*Component)(nil)
+)
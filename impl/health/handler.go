@@ -0,0 +1,116 @@
+/*
+Copyright 2024-present Volodymyr Konstanchuk and contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// FailedCheckMessage is the error message that handlers show instead of the real error of a failed check.
+const FailedCheckMessage = "health check failed"
+
+// NewLivenessHandler returns an HTTP handler that renders the report of liveness checks as JSON.
+// See NewReadinessHandler for more information.
+func NewLivenessHandler(registry Registry, errorWriter io.Writer) http.Handler {
+	return newHandler(registry, Liveness, registry.Liveness, errorWriter)
+}
+
+// NewReadinessHandler returns an HTTP handler that renders the report of readiness checks as JSON.
+// The response status is 200 if all health checks are successful and 503 otherwise.
+// You can pass the name of a single health check in the "name" query parameter.
+// The response contains only FailedCheckMessage for failed checks.
+// The details of errors are written to errorWriter, which may be nil.
+func NewReadinessHandler(registry Registry, errorWriter io.Writer) http.Handler {
+	return newHandler(registry, Readiness, registry.Readiness, errorWriter)
+}
+
+type reportView struct {
+	Status  Status        `json:"status"`
+	Results []*resultView `json:"checks"`
+}
+
+type resultView struct {
+	Name      string        `json:"name"`
+	Version   string        `json:"version,omitempty"`
+	Status    Status        `json:"status"`
+	Message   string        `json:"error,omitempty"`
+	Duration  time.Duration `json:"duration"`
+	CheckedAt time.Time     `json:"checkedAt"`
+}
+
+func newHandler(registry Registry, kind Kind, check func(ctx context.Context) *Report, errorWriter io.Writer) http.Handler {
+	return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		var (
+			status Status
+			data   any
+		)
+		if name := request.URL.Query().Get("name"); name != "" {
+			result, err := registry.CheckOne(request.Context(), kind, name)
+			if err != nil {
+				logError(errorWriter, kind, name, err)
+				http.Error(writer, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+				return
+			}
+			status, data = result.Status, newResultView(result, kind, errorWriter)
+		} else {
+			report := check(request.Context())
+			view := &reportView{
+				Status:  report.Status,
+				Results: make([]*resultView, 0, len(report.Results)),
+			}
+			for _, result := range report.Results {
+				view.Results = append(view.Results, newResultView(result, kind, errorWriter))
+			}
+			status, data = report.Status, view
+		}
+		writer.Header().Set("Content-Type", "application/json")
+		writer.Header().Set("Cache-Control", "no-store")
+		if status == StatusUp {
+			writer.WriteHeader(http.StatusOK)
+		} else {
+			writer.WriteHeader(http.StatusServiceUnavailable)
+		}
+		_ = json.NewEncoder(writer).Encode(data)
+	})
+}
+
+func newResultView(result *Result, kind Kind, errorWriter io.Writer) *resultView {
+	view := &resultView{
+		Name:      result.Name,
+		Version:   result.Version,
+		Status:    result.Status,
+		Duration:  result.Duration,
+		CheckedAt: result.CheckedAt,
+	}
+	if result.Error != nil {
+		// Errors may contain internal details, so they are not shown to the client.
+		view.Message = FailedCheckMessage
+		logError(errorWriter, kind, result.Name, result.Error)
+	}
+	return view
+}
+
+func logError(errorWriter io.Writer, kind Kind, name string, err error) {
+	if errorWriter != nil {
+		_, _ = fmt.Fprintf(errorWriter, "The %s check '%s' failed: %s\n", kind, name, err)
+	}
+}
@@ -0,0 +1,278 @@
+/*
+Copyright 2024-present Volodymyr Konstanchuk and contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package health
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/componego/componego"
+	"github.com/componego/componego/impl/driver"
+	"github.com/componego/componego/impl/environment/managers/component"
+	"github.com/componego/componego/libs/xerrors"
+)
+
+var (
+	ErrHealthCheck   = xerrors.New("health check failed", "E0610")
+	ErrCheckTimeout  = ErrHealthCheck.WithMessage("health check timeout exceeded", "E0611")
+	ErrUnknownCheck  = ErrHealthCheck.WithMessage("unknown health check", "E0612")
+	ErrDuplicateName = ErrHealthCheck.WithMessage("health check with the same name already exists", "E0613")
+)
+
+// Status is the status of a health check.
+type Status string
+
+const (
+	StatusUp   Status = "up"
+	StatusDown Status = "down"
+)
+
+// Kind is the kind of health checks.
+type Kind string
+
+const (
+	// Liveness checks report whether the application is broken and must be restarted.
+	Liveness Kind = "liveness"
+	// Readiness checks report whether the application is ready to work, including its external services.
+	Readiness Kind = "readiness"
+)
+
+// Check is a function that returns an error if something cannot work properly.
+type Check = func(ctx context.Context) error
+
+// Registry is an interface that describes lists of liveness and readiness checks.
+type Registry interface {
+	// RegisterLiveness adds a liveness check that does not belong to any component.
+	RegisterLiveness(name string, check Check) error
+	// RegisterReadiness adds a readiness check that does not belong to any component.
+	RegisterReadiness(name string, check Check) error
+	// Liveness runs all liveness checks and returns the report.
+	Liveness(ctx context.Context) *Report
+	// Readiness runs all readiness checks and returns the report.
+	Readiness(ctx context.Context) *Report
+	// CheckOne runs the health check of the kind with the name and returns the result.
+	CheckOne(ctx context.Context, kind Kind, name string) (*Result, error)
+}
+
+// Options are the registry options.
+type Options struct {
+	// Timeout is the maximum duration of each health check. Zero means no timeout.
+	Timeout time.Duration
+	// CacheDuration is the duration during which the result of each health check is reused. Zero disables the cache.
+	CacheDuration time.Duration
+}
+
+// Report is the result of all health checks of the same kind.
+type Report struct {
+	// Status is StatusUp only if all health checks are successful.
+	Status Status
+	// Results are sorted in the order of components. Other health checks are added at the end.
+	Results []*Result
+}
+
+// Result is the result of a health check.
+type Result struct {
+	Name      string
+	Version   string
+	Status    Status
+	Error     error
+	Duration  time.Duration
+	CheckedAt time.Time
+}
+
+type registry struct {
+	options *Options
+	mutex   sync.Mutex
+	lists   map[Kind]*checkList
+}
+
+type checkList struct {
+	names []string
+	items map[string]*registryItem
+}
+
+type registryItem struct {
+	version string
+	check   Check
+	result  *Result
+}
+
+// NewRegistry creates a registry with the health checks of all active components.
+// Components that implement componego.ComponentLiveness provide liveness checks,
+// and components that implement componego.ComponentHealth provide readiness checks.
+func NewRegistry(env componego.Environment, options *Options) Registry {
+	if options == nil {
+		options = &Options{}
+	}
+	r := &registry{
+		options: options,
+		lists: map[Kind]*checkList{
+			Liveness:  newCheckList(),
+			Readiness: newCheckList(),
+		},
+	}
+	for _, item := range env.Components() {
+		if item, ok := component.Implements[componego.ComponentLiveness](item); ok {
+			r.lists[Liveness].add(item.ComponentIdentifier(), item.ComponentVersion(), item.ComponentLivenessCheck)
+		}
+		if item, ok := component.Implements[componego.ComponentHealth](item); ok {
+			r.lists[Readiness].add(item.ComponentIdentifier(), item.ComponentVersion(), item.ComponentHealthCheck)
+		}
+	}
+	return r
+}
+
+// RegisterLiveness belongs to interface Registry.
+func (r *registry) RegisterLiveness(name string, check Check) error {
+	return r.register(Liveness, name, check)
+}
+
+// RegisterReadiness belongs to interface Registry.
+func (r *registry) RegisterReadiness(name string, check Check) error {
+	return r.register(Readiness, name, check)
+}
+
+// Liveness belongs to interface Registry.
+func (r *registry) Liveness(ctx context.Context) *Report {
+	return r.check(ctx, Liveness)
+}
+
+// Readiness belongs to interface Registry.
+func (r *registry) Readiness(ctx context.Context) *Report {
+	return r.check(ctx, Readiness)
+}
+
+// CheckOne belongs to interface Registry.
+func (r *registry) CheckOne(ctx context.Context, kind Kind, name string) (*Result, error) {
+	r.mutex.Lock()
+	var (
+		item   *registryItem
+		result *Result
+		ok     bool
+	)
+	if list, hasList := r.lists[kind]; hasList {
+		item, ok = list.items[name]
+	}
+	if ok {
+		result = item.result
+	}
+	r.mutex.Unlock()
+	if !ok {
+		return nil, ErrUnknownCheck.WithOptions("E0615",
+			xerrors.NewOption("componego:health:kind", kind),
+			xerrors.NewOption("componego:health:name", name),
+		)
+	}
+	if result != nil && r.options.CacheDuration > 0 && time.Since(result.CheckedAt) < r.options.CacheDuration {
+		return result, nil
+	}
+	result = &Result{
+		Name:      name,
+		Version:   item.version,
+		Status:    StatusUp,
+		CheckedAt: time.Now(),
+	}
+	result.Error = r.runCheck(ctx, item.check)
+	result.Duration = time.Since(result.CheckedAt)
+	if result.Error != nil {
+		result.Status = StatusDown
+	}
+	r.mutex.Lock()
+	item.result = result
+	r.mutex.Unlock()
+	return result, nil
+}
+
+func (r *registry) register(kind Kind, name string, check Check) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	list := r.lists[kind]
+	if _, ok := list.items[name]; ok {
+		return ErrDuplicateName.WithOptions("E0614",
+			xerrors.NewOption("componego:health:kind", kind),
+			xerrors.NewOption("componego:health:name", name),
+		)
+	}
+	list.add(name, "", check)
+	return nil
+}
+
+func (r *registry) check(ctx context.Context, kind Kind) *Report {
+	r.mutex.Lock()
+	names := make([]string, len(r.lists[kind].names))
+	copy(names, r.lists[kind].names)
+	r.mutex.Unlock()
+	report := &Report{
+		Status:  StatusUp,
+		Results: make([]*Result, len(names)),
+	}
+	waitGroup := &sync.WaitGroup{}
+	waitGroup.Add(len(names))
+	for i, name := range names {
+		go func(i int, name string) {
+			defer waitGroup.Done()
+			report.Results[i], _ = r.CheckOne(ctx, kind, name)
+		}(i, name) // We support compatibility with older versions of the language.
+	}
+	waitGroup.Wait()
+	for _, result := range report.Results {
+		if result.Status != StatusUp {
+			report.Status = StatusDown
+		}
+	}
+	return report
+}
+
+func (r *registry) runCheck(ctx context.Context, check Check) error {
+	if r.options.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, r.options.Timeout)
+		defer cancel()
+	}
+	result := make(chan error, 1)
+	go func() {
+		var err error
+		defer func() {
+			// Panics cannot leave the goroutine, so we convert them into errors.
+			result <- driver.ErrorRecoveryOnStop(recover(), err)
+		}()
+		err = check(ctx)
+	}()
+	// The check may ignore the context, so we do not wait for it after the timeout.
+	select {
+	case err := <-result:
+		return err
+	case <-ctx.Done():
+		return ErrCheckTimeout.WithError(ctx.Err(), "E0616")
+	}
+}
+
+func newCheckList() *checkList {
+	return &checkList{
+		names: make([]string, 0),
+		items: make(map[string]*registryItem),
+	}
+}
+
+func (c *checkList) add(name string, version string, check Check) {
+	c.names = append(c.names, name)
+	c.items[name] = &registryItem{
+		version: version,
+		check:   check,
+	}
+}
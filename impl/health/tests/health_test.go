@@ -0,0 +1,258 @@
+/*
+Copyright 2024-present Volodymyr Konstanchuk and contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tests
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/componego/componego"
+	"github.com/componego/componego/impl/application"
+	"github.com/componego/componego/impl/environment/managers/component"
+	"github.com/componego/componego/impl/environment/managers/dependency"
+	"github.com/componego/componego/impl/health"
+	"github.com/componego/componego/internal/testing/require"
+	"github.com/componego/componego/tests/runner"
+)
+
+type healthComponent struct {
+	componego.Component
+	check func(ctx context.Context) error
+}
+
+func (h *healthComponent) ComponentHealthCheck(ctx context.Context) error {
+	return h.check(ctx)
+}
+
+func newHealthComponent(identifier string, check func(ctx context.Context) error) componego.Component {
+	return &healthComponent{
+		Component: component.NewFactory(identifier, "1.0.0").Build(),
+		check:     check,
+	}
+}
+
+func createEnvironment(t *testing.T, components ...componego.Component) componego.Environment {
+	appFactory := application.NewFactory("Health Test Application")
+	appFactory.SetApplicationComponents(func() ([]componego.Component, error) {
+		return components, nil
+	})
+	env, cancelEnv := runner.CreateTestEnvironment(t, appFactory.Build(), nil)
+	t.Cleanup(cancelEnv)
+	return env
+}
+
+func TestRegistry(t *testing.T) {
+	customErr := errors.New("custom error")
+
+	t.Run("component checks", func(t *testing.T) {
+		env := createEnvironment(t,
+			newHealthComponent("component 1", func(_ context.Context) error {
+				return nil
+			}),
+			component.NewFactory("component 2", "1.0.0").Build(),
+			newHealthComponent("component 3", func(_ context.Context) error {
+				return customErr
+			}),
+		)
+		report := health.NewRegistry(env, nil).Readiness(context.Background())
+		require.Equal(t, health.StatusDown, report.Status)
+		require.Len(t, report.Results, 2)
+		require.Equal(t, "component 1", report.Results[0].Name)
+		require.Equal(t, "1.0.0", report.Results[0].Version)
+		require.Equal(t, health.StatusUp, report.Results[0].Status)
+		require.NoError(t, report.Results[0].Error)
+		require.Equal(t, "component 3", report.Results[1].Name)
+		require.Equal(t, health.StatusDown, report.Results[1].Status)
+		require.ErrorIs(t, report.Results[1].Error, customErr)
+	})
+
+	t.Run("liveness and readiness checks", func(t *testing.T) {
+		factory := component.NewFactory("component 1", "1.0.0")
+		factory.SetComponentLiveness(func(_ context.Context) error {
+			return nil
+		})
+		factory.SetComponentHealth(func(_ context.Context) error {
+			return customErr
+		})
+		env := createEnvironment(t,
+			factory.Build(),
+			component.NewFactory("component 2", "1.0.0").Build(),
+		)
+		registry := health.NewRegistry(env, nil)
+		report := registry.Liveness(context.Background())
+		require.Equal(t, health.StatusUp, report.Status)
+		require.Len(t, report.Results, 1)
+		require.Equal(t, "component 1", report.Results[0].Name)
+		report = registry.Readiness(context.Background())
+		require.Equal(t, health.StatusDown, report.Status)
+		require.Len(t, report.Results, 1)
+		require.ErrorIs(t, report.Results[0].Error, customErr)
+		require.NoError(t, registry.RegisterLiveness("custom", func(_ context.Context) error {
+			return customErr
+		}))
+		require.Equal(t, health.StatusDown, registry.Liveness(context.Background()).Status)
+		require.Len(t, registry.Readiness(context.Background()).Results, 1)
+		_, err := registry.CheckOne(context.Background(), health.Readiness, "custom")
+		require.ErrorIs(t, err, health.ErrUnknownCheck)
+	})
+
+	t.Run("registered checks", func(t *testing.T) {
+		env := createEnvironment(t, newHealthComponent("component", func(_ context.Context) error {
+			return nil
+		}))
+		registry := health.NewRegistry(env, nil)
+		require.NoError(t, registry.RegisterReadiness("custom", func(_ context.Context) error {
+			return nil
+		}))
+		err := registry.RegisterReadiness("component", func(_ context.Context) error {
+			return nil
+		})
+		require.ErrorIs(t, err, health.ErrDuplicateName)
+		report := registry.Readiness(context.Background())
+		require.Equal(t, health.StatusUp, report.Status)
+		require.Len(t, report.Results, 2)
+		require.Equal(t, "custom", report.Results[1].Name)
+		_, err = registry.CheckOne(context.Background(), health.Readiness, "unknown")
+		require.ErrorIs(t, err, health.ErrUnknownCheck)
+	})
+
+	t.Run("timeout and panic", func(t *testing.T) {
+		env := createEnvironment(t,
+			newHealthComponent("component 1", func(ctx context.Context) error {
+				<-ctx.Done()
+				time.Sleep(10 * time.Millisecond)
+				return nil
+			}),
+			newHealthComponent("component 2", func(_ context.Context) error {
+				panic("panic occurred")
+			}),
+		)
+		registry := health.NewRegistry(env, &health.Options{
+			Timeout: 10 * time.Millisecond,
+		})
+		result, err := registry.CheckOne(context.Background(), health.Readiness, "component 1")
+		require.NoError(t, err)
+		require.Equal(t, health.StatusDown, result.Status)
+		require.ErrorIs(t, result.Error, health.ErrCheckTimeout)
+		require.ErrorIs(t, result.Error, context.DeadlineExceeded)
+		result, err = registry.CheckOne(context.Background(), health.Readiness, "component 2")
+		require.NoError(t, err)
+		require.Equal(t, health.StatusDown, result.Status)
+		require.ErrorContains(t, result.Error, "panic occurred")
+	})
+
+	t.Run("cache", func(t *testing.T) {
+		calls := int32(0)
+		env := createEnvironment(t, newHealthComponent("component", func(_ context.Context) error {
+			atomic.AddInt32(&calls, 1)
+			return nil
+		}))
+		registry := health.NewRegistry(env, &health.Options{
+			CacheDuration: time.Hour,
+		})
+		registry.Readiness(context.Background())
+		registry.Readiness(context.Background())
+		require.Equal(t, int32(1), atomic.LoadInt32(&calls))
+		registry = health.NewRegistry(env, nil)
+		registry.Readiness(context.Background())
+		registry.Readiness(context.Background())
+		require.Equal(t, int32(3), atomic.LoadInt32(&calls))
+	})
+}
+
+func TestComponent(t *testing.T) {
+	env := createEnvironment(t,
+		health.NewComponent(nil),
+		newHealthComponent("component", func(_ context.Context) error {
+			return nil
+		}),
+	)
+	registry, err := dependency.Get[health.Registry](env)
+	require.NoError(t, err)
+	report := registry.Readiness(context.Background())
+	require.Len(t, report.Results, 1)
+	require.Equal(t, "component", report.Results[0].Name)
+}
+
+func TestHandler(t *testing.T) {
+	isHealthy := true
+	env := createEnvironment(t, newHealthComponent("component", func(_ context.Context) error {
+		if isHealthy {
+			return nil
+		}
+		return errors.New("component is not healthy")
+	}))
+	errorWriter := &bytes.Buffer{}
+	handler := health.NewReadinessHandler(health.NewRegistry(env, nil), errorWriter)
+
+	t.Run("all checks", func(t *testing.T) {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/health", nil))
+		require.Equal(t, http.StatusOK, recorder.Code)
+		require.Equal(t, "application/json", recorder.Header().Get("Content-Type"))
+		data := map[string]any{}
+		require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &data))
+		require.Equal(t, "up", data["status"])
+		require.Len(t, data["checks"], 1)
+	})
+
+	t.Run("single check", func(t *testing.T) {
+		isHealthy = false
+		defer func() {
+			isHealthy = true
+		}()
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/health?name=component", nil))
+		require.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+		data := map[string]any{}
+		require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &data))
+		require.Equal(t, "down", data["status"])
+		require.Equal(t, health.FailedCheckMessage, data["error"])
+		require.Contains(t, errorWriter.String(), "component is not healthy")
+	})
+
+	t.Run("unknown check", func(t *testing.T) {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/health?name=unknown", nil))
+		require.Equal(t, http.StatusNotFound, recorder.Code)
+		require.Equal(t, http.StatusText(http.StatusNotFound)+"\n", recorder.Body.String())
+		require.Contains(t, errorWriter.String(), "unknown health check")
+	})
+
+	t.Run("liveness handler", func(t *testing.T) {
+		isHealthy = false
+		defer func() {
+			isHealthy = true
+		}()
+		recorder := httptest.NewRecorder()
+		health.NewLivenessHandler(health.NewRegistry(env, nil), nil).ServeHTTP(
+			recorder, httptest.NewRequest(http.MethodGet, "/livez", nil),
+		)
+		require.Equal(t, http.StatusOK, recorder.Code)
+		data := map[string]any{}
+		require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &data))
+		require.Equal(t, "up", data["status"])
+		require.Len(t, data["checks"], 0)
+	})
+}
@@ -18,22 +18,33 @@ package runner
 
 import (
 	"context"
+	"errors"
 	"os"
 	"os/signal"
 	"runtime"
+	"strings"
 	"syscall"
 
 	"github.com/componego/componego"
 	"github.com/componego/componego/impl/driver"
+	"github.com/componego/componego/impl/environment/managers/config"
 	"github.com/componego/componego/impl/runner/unhandled-errors"
 	"github.com/componego/componego/internal/developer"
 	"github.com/componego/componego/internal/system"
 	"github.com/componego/componego/internal/utils"
 )
 
+// ConfigDumpFlag is a command line argument that prints the configuration of the application instead of running it.
+// The format can be passed after the equals sign, for example, --componego:config-dump=flat.
+// See config.Dump for the available formats.
+const ConfigDumpFlag = "--componego:config-dump"
+
 // RunWithContext runs the application with context and returns the exit code.
 func RunWithContext(ctx context.Context, app componego.Application, appMode componego.ApplicationMode) int {
 	d := driver.New(nil)
+	if format, ok := getConfigDumpFormat(os.Args[1:]); ok {
+		return dumpConfig(ctx, d, app, appMode, format)
+	}
 	exitCode, err := d.RunApplication(ctx, app, appMode)
 	if err != nil {
 		// Here we display all errors that were not processed.
@@ -75,6 +86,39 @@ func RunGracefullyAndExit(app componego.Application, appMode componego.Applicati
 	exit(exitCode, appMode)
 }
 
+// dumpConfig prints the configuration of the application. The application action is not run.
+func dumpConfig(ctx context.Context, d driver.Driver, app componego.Application, appMode componego.ApplicationMode, format string) int {
+	env, cancelEnv, err := d.CreateEnvironment(ctx, app, appMode)
+	if err == nil {
+		var dump string
+		dump, err = config.Dump(env, &config.DumpOptions{
+			Format:  format,
+			Origins: appMode == componego.DeveloperMode,
+		})
+		if err == nil {
+			utils.Fprint(system.Stdout, dump)
+		}
+		err = errors.Join(err, cancelEnv())
+	}
+	if err != nil {
+		utils.Fprint(system.Stderr, unhandled_errors.ToString(err, appMode, unhandled_errors.GetHandlers()))
+		return componego.ErrorExitCode
+	}
+	return componego.SuccessExitCode
+}
+
+func getConfigDumpFormat(args []string) (string, bool) {
+	for _, arg := range args {
+		if arg == ConfigDumpFlag {
+			return "", true
+		}
+		if format, ok := strings.CutPrefix(arg, ConfigDumpFlag+"="); ok {
+			return format, true
+		}
+	}
+	return "", false
+}
+
 func exit(exitCode int, appMode componego.ApplicationMode) {
 	if appMode == componego.DeveloperMode && system.NumGoroutineBeforeExit() > 1 {
 		// In any case, all goroutines will be terminated after exiting the application, but we will show this message.
@@ -0,0 +1,172 @@
+/*
+Copyright 2024-present Volodymyr Konstanchuk and contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package semver
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Constraint is a condition that the version must satisfy.
+type Constraint interface {
+	// Check returns true if the version satisfies the constraint.
+	Check(version *Version) bool
+	// String returns the original constraint.
+	String() string
+}
+
+// ParseConstraint parses the constraint.
+// Conditions separated by commas must all be satisfied. Groups of conditions can be separated by '||'.
+// The supported operators are '=', '!=', '>', '>=', '<', '<=', '~' (patch updates) and '^' (compatible updates).
+// For example, '>= 1.2.0, < 2 || ^3.1'.
+func ParseConstraint(constraint string) (Constraint, error) {
+	result := &orConstraint{
+		original: constraint,
+	}
+	for _, group := range strings.Split(constraint, "||") {
+		andGroup := andConstraint{}
+		for _, condition := range strings.Split(group, ",") {
+			items, err := parseCondition(strings.TrimSpace(condition))
+			if err != nil {
+				return nil, fmt.Errorf("invalid constraint '%s': %w", constraint, err)
+			}
+			andGroup = append(andGroup, items...)
+		}
+		result.groups = append(result.groups, andGroup)
+	}
+	return result, nil
+}
+
+// MustParseConstraint is similar to ParseConstraint, but panics if the constraint is invalid.
+func MustParseConstraint(constraint string) Constraint {
+	result, err := ParseConstraint(constraint)
+	if err != nil {
+		panic(err)
+	}
+	return result
+}
+
+type orConstraint struct {
+	original string
+	groups   []andConstraint
+}
+
+func (o *orConstraint) Check(version *Version) bool {
+	for _, group := range o.groups {
+		if group.check(version) {
+			return true
+		}
+	}
+	return false
+}
+
+func (o *orConstraint) String() string {
+	return o.original
+}
+
+type andConstraint []*condition
+
+func (a andConstraint) check(version *Version) bool {
+	for _, item := range a {
+		if !item.check(version) {
+			return false
+		}
+	}
+	return true
+}
+
+type condition struct {
+	operator string
+	version  *Version
+}
+
+func (c *condition) check(version *Version) bool {
+	result := version.Compare(c.version)
+	switch c.operator {
+	case "=":
+		return result == 0
+	case "!=":
+		return result != 0
+	case ">":
+		return result > 0
+	case ">=":
+		return result >= 0
+	case "<":
+		return result < 0
+	case "<=":
+		return result <= 0
+	}
+	return false
+}
+
+func parseCondition(value string) ([]*condition, error) {
+	if value == "" {
+		return nil, fmt.Errorf("empty condition")
+	} else if value == "*" {
+		return nil, nil
+	}
+	operator := "="
+	// Longer operators must be checked first.
+	for _, item := range [...]string{">=", "<=", "!=", "==", ">", "<", "=", "~", "^"} {
+		if strings.HasPrefix(value, item) {
+			operator = item
+			value = strings.TrimSpace(value[len(item):])
+			break
+		}
+	}
+	version, parts, err := parse(value)
+	if err != nil {
+		return nil, err
+	}
+	switch operator {
+	case "==":
+		operator = "="
+	case "<":
+		if version.PreRelease == "" {
+			// '< 2' excludes pre-releases of 2.0.0 unless the constraint names a pre-release.
+			version.PreRelease = "0"
+		}
+	case "~":
+		// ~1.2.3 is >=1.2.3 <1.3.0, and ~1 is >=1.0.0 <2.0.0.
+		upper := &Version{Major: version.Major + 1}
+		if parts > 1 {
+			upper = &Version{Major: version.Major, Minor: version.Minor + 1}
+		}
+		return rangeConditions(version, upper), nil
+	case "^":
+		// ^1.2.3 is >=1.2.3 <2.0.0, ^0.2.3 is >=0.2.3 <0.3.0 and ^0.0.3 is >=0.0.3 <0.0.4.
+		upper := &Version{Major: version.Major + 1}
+		if version.Major == 0 && parts > 1 {
+			if version.Minor == 0 && parts > 2 {
+				upper = &Version{Patch: version.Patch + 1}
+			} else {
+				upper = &Version{Minor: version.Minor + 1}
+			}
+		}
+		return rangeConditions(version, upper), nil
+	}
+	return []*condition{{operator: operator, version: version}}, nil
+}
+
+func rangeConditions(lower *Version, upper *Version) []*condition {
+	// The upper bound excludes pre-releases of the next version.
+	upper.PreRelease = "0"
+	return []*condition{
+		{operator: ">=", version: lower},
+		{operator: "<", version: upper},
+	}
+}
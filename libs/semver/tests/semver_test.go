@@ -0,0 +1,176 @@
+/*
+Copyright 2024-present Volodymyr Konstanchuk and contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tests
+
+import (
+	"testing"
+
+	"github.com/componego/componego/internal/testing/require"
+	"github.com/componego/componego/libs/semver"
+)
+
+func TestParse(t *testing.T) {
+	t.Run("valid versions", func(t *testing.T) {
+		testCases := map[string]string{
+			"1.2.3":             "1.2.3",
+			"v1.2.3":            "1.2.3",
+			"1.2":               "1.2.0",
+			"1":                 "1.0.0",
+			"1.2.3-beta.1":      "1.2.3-beta.1",
+			"1.2.3-rc.1+build5": "1.2.3-rc.1",
+			" 0.0.1 ":           "0.0.1",
+		}
+		for version, expected := range testCases {
+			actual, err := semver.Parse(version)
+			require.NoError(t, err)
+			require.Equal(t, expected, actual.String())
+		}
+	})
+
+	t.Run("invalid versions", func(t *testing.T) {
+		for _, version := range []string{"", "a.b.c", "1.2.3.4", "1.2.3-", "1..2", "-1.2.3"} {
+			_, err := semver.Parse(version)
+			require.Error(t, err, version)
+			require.Panics(t, func() {
+				semver.MustParse(version)
+			})
+		}
+	})
+}
+
+func TestCompare(t *testing.T) {
+	// Each version is greater than the previous one.
+	versions := []string{
+		"0.0.1",
+		"0.1.0",
+		"1.0.0-alpha",
+		"1.0.0-alpha.1",
+		"1.0.0-alpha.beta",
+		"1.0.0-beta",
+		"1.0.0-beta.2",
+		"1.0.0-beta.11",
+		"1.0.0-rc.1",
+		"1.0.0",
+		"1.0.1",
+		"1.10.0",
+		"2.0.0",
+	}
+	for i := 0; i < len(versions); i++ {
+		for j := 0; j < len(versions); j++ {
+			expected := 0
+			if i < j {
+				expected = -1
+			} else if i > j {
+				expected = 1
+			}
+			actual := semver.MustParse(versions[i]).Compare(semver.MustParse(versions[j]))
+			require.Equal(t, expected, actual, versions[i]+" <=> "+versions[j])
+		}
+	}
+	require.Equal(t, 0, semver.MustParse("1.0.0+build1").Compare(semver.MustParse("1.0.0+build2")))
+}
+
+func TestParseConstraint(t *testing.T) {
+	t.Run("constraint checks", func(t *testing.T) {
+		testCases := [...]struct {
+			constraint string
+			satisfied  []string
+			violated   []string
+		}{
+			{
+				constraint: ">= 1.2.0, < 2",
+				satisfied:  []string{"1.2.0", "1.9.9", "1.2.1-beta"},
+				violated:   []string{"1.1.9", "2.0.0", "1.2.0-beta", "2.0.0-rc.1", "2.0.0-0"},
+			},
+			{
+				constraint: "< 2.0.0-rc.2",
+				satisfied:  []string{"1.9.9", "2.0.0-rc.1", "2.0.0-beta"},
+				violated:   []string{"2.0.0-rc.2", "2.0.0"},
+			},
+			{
+				constraint: "1.2.3",
+				satisfied:  []string{"1.2.3", "v1.2.3+build"},
+				violated:   []string{"1.2.4", "1.2.3-beta"},
+			},
+			{
+				constraint: "!=1.0.0",
+				satisfied:  []string{"1.0.1", "0.9.0"},
+				violated:   []string{"1.0.0"},
+			},
+			{
+				constraint: ">1, <=3",
+				satisfied:  []string{"1.0.1", "3.0.0"},
+				violated:   []string{"1.0.0", "3.0.1"},
+			},
+			{
+				constraint: "~1.2.3",
+				satisfied:  []string{"1.2.3", "1.2.99"},
+				violated:   []string{"1.2.2", "1.3.0", "1.3.0-beta"},
+			},
+			{
+				constraint: "~1",
+				satisfied:  []string{"1.0.0", "1.99.0"},
+				violated:   []string{"0.9.0", "2.0.0"},
+			},
+			{
+				constraint: "^1.2.3",
+				satisfied:  []string{"1.2.3", "1.9.0"},
+				violated:   []string{"1.2.2", "2.0.0"},
+			},
+			{
+				constraint: "^0.2.3",
+				satisfied:  []string{"0.2.3", "0.2.9"},
+				violated:   []string{"0.3.0", "0.2.2"},
+			},
+			{
+				constraint: "^0.0.3",
+				satisfied:  []string{"0.0.3"},
+				violated:   []string{"0.0.4", "0.0.2"},
+			},
+			{
+				constraint: "^1.0 || ^3.1",
+				satisfied:  []string{"1.5.0", "3.1.0", "3.9.0"},
+				violated:   []string{"2.0.0", "3.0.0", "4.0.0"},
+			},
+			{
+				constraint: "*",
+				satisfied:  []string{"0.0.0", "99.0.0"},
+			},
+		}
+		for _, testCase := range testCases {
+			constraint, err := semver.ParseConstraint(testCase.constraint)
+			require.NoError(t, err)
+			require.Equal(t, testCase.constraint, constraint.String())
+			for _, version := range testCase.satisfied {
+				require.True(t, constraint.Check(semver.MustParse(version)), testCase.constraint+" "+version)
+			}
+			for _, version := range testCase.violated {
+				require.False(t, constraint.Check(semver.MustParse(version)), testCase.constraint+" "+version)
+			}
+		}
+	})
+
+	t.Run("invalid constraints", func(t *testing.T) {
+		for _, constraint := range []string{"", ">=", ">= 1.0,", "> a", "1.0 ||"} {
+			_, err := semver.ParseConstraint(constraint)
+			require.Error(t, err, constraint)
+			require.Panics(t, func() {
+				semver.MustParseConstraint(constraint)
+			})
+		}
+	})
+}
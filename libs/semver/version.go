@@ -0,0 +1,143 @@
+/*
+Copyright 2024-present Volodymyr Konstanchuk and contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package semver
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Version is a parsed semantic version.
+// See https://semver.org for more information.
+type Version struct {
+	Major      uint64
+	Minor      uint64
+	Patch      uint64
+	PreRelease string
+}
+
+// Parse parses the version.
+// The leading 'v' and the build metadata are allowed. Missing minor and patch numbers are equal to zero.
+func Parse(version string) (*Version, error) {
+	result, _, err := parse(version)
+	return result, err
+}
+
+// MustParse is similar to Parse, but panics if the version is invalid.
+func MustParse(version string) *Version {
+	result, err := Parse(version)
+	if err != nil {
+		panic(err)
+	}
+	return result
+}
+
+// String returns the text representation of the version.
+func (v *Version) String() string {
+	result := strconv.FormatUint(v.Major, 10) + "." + strconv.FormatUint(v.Minor, 10) + "." + strconv.FormatUint(v.Patch, 10)
+	if v.PreRelease != "" {
+		result += "-" + v.PreRelease
+	}
+	return result
+}
+
+// Compare returns -1, 0 or +1 depending on whether the version is less than, equal to, or greater than another version.
+func (v *Version) Compare(other *Version) int {
+	if result := compareNumbers(v.Major, other.Major); result != 0 {
+		return result
+	} else if result = compareNumbers(v.Minor, other.Minor); result != 0 {
+		return result
+	} else if result = compareNumbers(v.Patch, other.Patch); result != 0 {
+		return result
+	}
+	return comparePreReleases(v.PreRelease, other.PreRelease)
+}
+
+// parse returns the version and the number of parts that were specified in the version.
+func parse(version string) (*Version, int, error) {
+	original := version
+	version = strings.TrimPrefix(strings.TrimSpace(version), "v")
+	if index := strings.IndexByte(version, '+'); index >= 0 {
+		// Build metadata does not affect the version precedence.
+		version = version[:index]
+	}
+	result := &Version{}
+	if index := strings.IndexByte(version, '-'); index >= 0 {
+		result.PreRelease = version[index+1:]
+		version = version[:index]
+		if result.PreRelease == "" {
+			return nil, 0, fmt.Errorf("invalid version '%s': empty pre-release", original)
+		}
+	}
+	parts := strings.Split(version, ".")
+	if len(parts) > 3 {
+		return nil, 0, fmt.Errorf("invalid version '%s': too many parts", original)
+	}
+	numbers := [3]*uint64{&result.Major, &result.Minor, &result.Patch}
+	for i, part := range parts {
+		number, err := strconv.ParseUint(part, 10, 64)
+		if err != nil {
+			return nil, 0, fmt.Errorf("invalid version '%s': '%s' is not a number", original, part)
+		}
+		*numbers[i] = number
+	}
+	return result, len(parts), nil
+}
+
+func compareNumbers(a, b uint64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+func comparePreReleases(a, b string) int {
+	// A version without a pre-release has a higher precedence.
+	if a == b {
+		return 0
+	} else if a == "" {
+		return 1
+	} else if b == "" {
+		return -1
+	}
+	aParts := strings.Split(a, ".")
+	bParts := strings.Split(b, ".")
+	for i := 0; i < len(aParts) && i < len(bParts); i++ {
+		aNumber, aErr := strconv.ParseUint(aParts[i], 10, 64)
+		bNumber, bErr := strconv.ParseUint(bParts[i], 10, 64)
+		switch {
+		case aErr == nil && bErr == nil:
+			if result := compareNumbers(aNumber, bNumber); result != 0 {
+				return result
+			}
+			continue
+		case aErr == nil:
+			// Numeric identifiers always have lower precedence than alphanumeric identifiers.
+			return -1
+		case bErr == nil:
+			return 1
+		}
+		if result := strings.Compare(aParts[i], bParts[i]); result != 0 {
+			return result
+		}
+	}
+	return compareNumbers(uint64(len(aParts)), uint64(len(bParts)))
+}